@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/vinimax001/finance-tracker/internal/finance"
@@ -78,7 +79,87 @@ func main() {
 		log.Println("storage=memory")
 	}
 
-	svc := finance.NewService(repo)
+	var opts []finance.Option
+	if v := os.Getenv("MAX_FUTURE_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			log.Fatalf("invalid MAX_FUTURE_DAYS value: %q", v)
+		}
+		opts = append(opts, finance.WithMaxFuture(time.Duration(days)*24*time.Hour))
+	}
+
+	if v := os.Getenv("BASE_CURRENCY"); v != "" {
+		opts = append(opts, finance.WithBaseCurrency(v))
+	}
+
+	if v := os.Getenv("IDEMPOTENCY_TTL_HOURS"); v != "" {
+		hours, err := strconv.Atoi(v)
+		if err != nil || hours <= 0 {
+			log.Fatalf("invalid IDEMPOTENCY_TTL_HOURS value: %q", v)
+		}
+		opts = append(opts, finance.WithIdempotencyTTL(time.Duration(hours)*time.Hour))
+	}
+
+	// Sem AUTH_SECRET cada processo assina com uma chave aleatória: com várias instâncias ou
+	// após um restart os tokens emitidos deixam de valer, então só o modo memory aceita isso
+	if v := os.Getenv("AUTH_SECRET"); v != "" {
+		opts = append(opts, finance.WithAuthSecret([]byte(v)))
+	} else if storage != "memory" {
+		log.Fatalf("AUTH_SECRET environment variable is required when STORAGE=%s", storage)
+	} else {
+		log.Println("AUTH_SECRET not set, using a random key: tokens will not survive restarts")
+	}
+
+	if v := os.Getenv("AUTH_TOKEN_TTL_HOURS"); v != "" {
+		hours, err := strconv.Atoi(v)
+		if err != nil || hours <= 0 {
+			log.Fatalf("invalid AUTH_TOKEN_TTL_HOURS value: %q", v)
+		}
+		opts = append(opts, finance.WithTokenTTL(time.Duration(hours)*time.Hour))
+	}
+
+	if v := os.Getenv("CATEGORY_AUTO_CREATE"); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("invalid CATEGORY_AUTO_CREATE value: %q", v)
+		}
+		opts = append(opts, finance.WithCategoryAutoCreate(on))
+	}
+
+	svc := finance.NewService(repo, opts...)
+
+	// Limpeza periódica das Idempotency-Keys expiradas
+	go func() {
+		for range time.Tick(time.Hour) {
+			if n, err := svc.PurgeIdempotencyKeys(context.Background()); err != nil {
+				log.Printf("purge idempotency keys: %v", err)
+			} else if n > 0 {
+				log.Printf("purged %d expired idempotency keys", n)
+			}
+		}
+	}()
+
+	// Agendador das transações recorrentes; com várias instâncias, só uma executa por vez
+	recurringEvery := 15 * time.Minute
+	if v := os.Getenv("RECURRING_INTERVAL_MINUTES"); v != "" {
+		minutes, err := strconv.Atoi(v)
+		if err != nil || minutes <= 0 {
+			log.Fatalf("invalid RECURRING_INTERVAL_MINUTES value: %q", v)
+		}
+		recurringEvery = time.Duration(minutes) * time.Minute
+	}
+	go func() {
+		for {
+			n, err := svc.RunRecurring(context.Background(), time.Now().UTC())
+			if err != nil {
+				log.Printf("recurring transactions: %v", err)
+			}
+			if n > 0 {
+				log.Printf("created %d recurring transactions", n)
+			}
+			time.Sleep(recurringEvery)
+		}
+	}()
 	mux := httpapi.NewMux(svc)
 
 	srv := &http.Server{
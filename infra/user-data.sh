@@ -30,6 +30,8 @@ DB_HOST=rds-finance-tracker.cqh40koaapaj.us-east-1.rds.amazonaws.com
 DB_PORT=5432
 DB_NAME=financetracker
 HTTP_ADDR=:8080
+# obrigatório: a mesma chave em todas as instâncias (ex: openssl rand -hex 32)
+AUTH_SECRET=
 EOF
 
 sudo chmod 600 /opt/finance-tracker/finance-app.env
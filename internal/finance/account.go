@@ -0,0 +1,213 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountKind string
+
+const (
+	Checking   AccountKind = "checking"
+	Savings    AccountKind = "savings"
+	CreditCard AccountKind = "credit_card"
+	Cash       AccountKind = "cash"
+)
+
+// DefaultCurrency é a moeda assumida quando nenhuma é informada
+const DefaultCurrency = "BRL"
+
+type Account struct {
+	ID                  uuid.UUID   `json:"id"`
+	LedgerID            uuid.UUID   `json:"-"`
+	Name                string      `json:"name"`
+	Kind                AccountKind `json:"kind"`     // checking | savings | credit_card | cash
+	Currency            string      `json:"currency"` // ISO 4217, ex: BRL
+	OpeningBalanceCents int64       `json:"opening_balance_cents"`
+	ClosingDay          int         `json:"closing_day,omitempty"` // cartão: dia do fechamento da fatura
+	DueDay              int         `json:"due_day,omitempty"`     // cartão: dia do vencimento da fatura
+	CreatedAt           time.Time   `json:"created_at"`
+	UpdatedAt           time.Time   `json:"updated_at"`
+}
+
+// AccountBalance é o saldo de uma conta em um instante
+type AccountBalance struct {
+	AccountID    uuid.UUID `json:"account_id"`
+	Currency     string    `json:"currency"`
+	At           time.Time `json:"at"`
+	OpeningCents int64     `json:"opening_balance_cents"`
+	BalanceCents int64     `json:"balance_cents"`
+}
+
+type AccountRepository interface {
+	CreateAccount(ctx context.Context, a *Account) error
+	GetAccount(ctx context.Context, id uuid.UUID) (*Account, error)
+	ListAccounts(ctx context.Context) ([]Account, error)
+	UpdateAccount(ctx context.Context, a *Account) error
+	DeleteAccount(ctx context.Context, id uuid.UUID) error
+	// StatementPayments lista os pagamentos de fatura do cartão (pernas de entrada com
+	// Statement preenchido)
+	StatementPayments(ctx context.Context, cardID uuid.UUID) ([]Transaction, error)
+	// AccountMovement soma as entradas menos as saídas da conta até "at" (inclusive)
+	AccountMovement(ctx context.Context, id uuid.UUID, at time.Time) (int64, error)
+}
+
+type AccountInput struct {
+	Name                string
+	Kind                AccountKind
+	Currency            string
+	OpeningBalanceCents int64
+	ClosingDay          int // obrigatório para cartões de crédito
+	DueDay              int // obrigatório para cartões de crédito
+}
+
+type AccountPatch struct {
+	Name                *string
+	Kind                *AccountKind
+	Currency            *string
+	OpeningBalanceCents *int64
+	ClosingDay          *int
+	DueDay              *int
+}
+
+func validAccountKind(k AccountKind) bool {
+	switch k {
+	case Checking, Savings, CreditCard, Cash:
+		return true
+	}
+	return false
+}
+
+// normalizeCurrency valida um código ISO 4217 (três letras) e devolve em maiúsculas
+func normalizeCurrency(c string) (string, bool) {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	if len(c) != 3 {
+		return "", false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+	return c, true
+}
+
+func validateAccount(a *Account) error {
+	if a.Name == "" || !validAccountKind(a.Kind) {
+		return ErrBadRequest
+	}
+	cur, ok := normalizeCurrency(a.Currency)
+	if !ok {
+		return ErrBadRequest
+	}
+	a.Currency = cur
+	if a.Kind == CreditCard && (a.ClosingDay == 0 || a.DueDay == 0) {
+		return fmt.Errorf("%w: credit cards require closing_day and due_day", ErrBadRequest)
+	}
+	if a.Kind != CreditCard && (a.ClosingDay != 0 || a.DueDay != 0) {
+		return fmt.Errorf("%w: closing_day and due_day are only for credit cards", ErrBadRequest)
+	}
+	if a.ClosingDay < 0 || a.ClosingDay > 31 || a.DueDay < 0 || a.DueDay > 31 || (a.ClosingDay == 0) != (a.DueDay == 0) {
+		return fmt.Errorf("%w: closing_day and due_day must both be between 1 and 31", ErrBadRequest)
+	}
+	return nil
+}
+
+func (s *Service) CreateAccount(ctx context.Context, in AccountInput) (*Account, error) {
+	now := time.Now().UTC()
+	a := &Account{
+		ID:                  uuid.New(),
+		LedgerID:            ledgerScope(ctx),
+		Name:                strings.TrimSpace(in.Name),
+		Kind:                in.Kind,
+		Currency:            in.Currency,
+		OpeningBalanceCents: in.OpeningBalanceCents,
+		ClosingDay:          in.ClosingDay,
+		DueDay:              in.DueDay,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if a.Currency == "" {
+		a.Currency = DefaultCurrency
+	}
+	if err := validateAccount(a); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateAccount(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *Service) GetAccount(ctx context.Context, id uuid.UUID) (*Account, error) {
+	return s.repo.GetAccount(ctx, id)
+}
+
+func (s *Service) ListAccounts(ctx context.Context) ([]Account, error) {
+	return s.repo.ListAccounts(ctx)
+}
+
+// UpdateAccount aplica o patch; trocar a moeda de uma conta com transações, regras
+// recorrentes ou metas falha com ErrConflict, já que os valores gravados estão na moeda antiga
+func (s *Service) UpdateAccount(ctx context.Context, id uuid.UUID, p AccountPatch) (*Account, error) {
+	a, err := s.repo.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Name != nil {
+		a.Name = strings.TrimSpace(*p.Name)
+	}
+	if p.Kind != nil {
+		a.Kind = *p.Kind
+	}
+	if p.Currency != nil {
+		a.Currency = *p.Currency
+	}
+	if p.OpeningBalanceCents != nil {
+		a.OpeningBalanceCents = *p.OpeningBalanceCents
+	}
+	if p.ClosingDay != nil {
+		a.ClosingDay = *p.ClosingDay
+	}
+	if p.DueDay != nil {
+		a.DueDay = *p.DueDay
+	}
+	if err := validateAccount(a); err != nil {
+		return nil, err
+	}
+	a.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateAccount(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DeleteAccount falha com ErrConflict se ainda houver transações, regras recorrentes ou
+// metas vinculadas
+func (s *Service) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteAccount(ctx, id)
+}
+
+// Balance calcula o saldo da conta em "at": saldo de abertura + movimentações até o instante
+func (s *Service) Balance(ctx context.Context, id uuid.UUID, at time.Time) (*AccountBalance, error) {
+	a, err := s.repo.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	at = at.UTC()
+	mov, err := s.repo.AccountMovement(ctx, id, at)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountBalance{
+		AccountID:    a.ID,
+		Currency:     a.Currency,
+		At:           at,
+		OpeningCents: a.OpeningBalanceCents,
+		BalanceCents: a.OpeningBalanceCents + mov,
+	}, nil
+}
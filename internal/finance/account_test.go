@@ -0,0 +1,98 @@
+package finance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAccountBalance(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+
+	acc, err := s.CreateAccount(ctx, AccountInput{Name: "Nubank", Kind: Checking, OpeningBalanceCents: 10000})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if acc.Currency != DefaultCurrency {
+		t.Fatalf("currency: %q", acc.Currency)
+	}
+
+	d1 := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	d2 := time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)
+	mustCreate := func(in TxInput) {
+		t.Helper()
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatalf("create tx: %v", err)
+		}
+	}
+	mustCreate(TxInput{Type: Income, Category: "salary", AmountCents: 50000, OccurredAt: d1, AccountID: &acc.ID})
+	mustCreate(TxInput{Type: Expense, Category: "rent", AmountCents: 20000, OccurredAt: d2, AccountID: &acc.ID})
+	mustCreate(TxInput{Type: Expense, Category: "food", AmountCents: 999, OccurredAt: d2})
+
+	b, err := s.Balance(ctx, acc.ID, d1)
+	if err != nil || b.BalanceCents != 60000 {
+		t.Fatalf("balance at d1: err=%v b=%+v", err, b)
+	}
+	b, err = s.Balance(ctx, acc.ID, d2)
+	if err != nil || b.BalanceCents != 40000 {
+		t.Fatalf("balance at d2: err=%v b=%+v", err, b)
+	}
+
+	sum, err := s.MonthlySummary(ctx, 2025, 2, TxFilter{AccountID: &acc.ID}, "")
+	if err != nil || sum.Expense != 20000 || sum.CountTx != 1 {
+		t.Fatalf("filtered summary: err=%v sum=%+v", err, sum)
+	}
+
+	if err := s.DeleteAccount(ctx, acc.ID); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+
+	usd := "USD"
+	if _, err := s.UpdateAccount(ctx, acc.ID, AccountPatch{Currency: &usd}); err != ErrConflict {
+		t.Fatalf("currency change with transactions: %v", err)
+	}
+	name := "Nubank PJ"
+	if a, err := s.UpdateAccount(ctx, acc.ID, AccountPatch{Name: &name}); err != nil || a.Currency != DefaultCurrency {
+		t.Fatalf("rename: %+v %v", a, err)
+	}
+	empty, _ := s.CreateAccount(ctx, AccountInput{Name: "Wise", Kind: Checking})
+	if a, err := s.UpdateAccount(ctx, empty.ID, AccountPatch{Currency: &usd}); err != nil || a.Currency != "USD" {
+		t.Fatalf("currency change on empty account: %+v %v", a, err)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	s := NewService(NewMemoryRepo())
+	ctx := context.Background()
+
+	chk, _ := s.CreateAccount(ctx, AccountInput{Name: "Conta", Kind: Checking, OpeningBalanceCents: 100000})
+	sav, _ := s.CreateAccount(ctx, AccountInput{Name: "Poupança", Kind: Savings})
+	at := time.Date(2025, 5, 5, 12, 0, 0, 0, time.UTC)
+
+	tr, err := s.CreateTransfer(ctx, TransferInput{FromAccountID: chk.ID, ToAccountID: sav.ID, AmountCents: 30000, OccurredAt: at})
+	if err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+
+	if b, _ := s.Balance(ctx, chk.ID, at); b.BalanceCents != 70000 {
+		t.Fatalf("checking balance: %d", b.BalanceCents)
+	}
+	if b, _ := s.Balance(ctx, sav.ID, at); b.BalanceCents != 30000 {
+		t.Fatalf("savings balance: %d", b.BalanceCents)
+	}
+	sum, _ := s.MonthlySummary(ctx, 2025, 5, TxFilter{}, "")
+	if sum.Income != 0 || sum.Expense != 0 {
+		t.Fatalf("transfer leaked into summary: %+v", sum)
+	}
+
+	if err := s.Delete(ctx, tr.In.ID); err != nil {
+		t.Fatalf("delete leg: %v", err)
+	}
+	if _, err := s.Get(ctx, tr.Out.ID); err != ErrNotFound {
+		t.Fatalf("expected out leg removed, got %v", err)
+	}
+	if _, err := s.CreateTransfer(ctx, TransferInput{FromAccountID: chk.ID, ToAccountID: chk.ID, AmountCents: 1}); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for same account, got %v", err)
+	}
+}
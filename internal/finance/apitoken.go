@@ -0,0 +1,175 @@
+package finance
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope limita o que um token de API pode fazer
+type Scope string
+
+const (
+	ScopeTransactionsRead  Scope = "transactions:read"
+	ScopeTransactionsWrite Scope = "transactions:write"
+	ScopeReportsRead       Scope = "reports:read"
+)
+
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeTransactionsRead, ScopeTransactionsWrite, ScopeReportsRead:
+		return true
+	}
+	return false
+}
+
+// APITokenPrefix distingue tokens de API dos tokens de sessão emitidos no login
+const APITokenPrefix = "ft_"
+
+// DefaultAPITokenTTL é a validade de um token de API criado sem expires_at
+const DefaultAPITokenTTL = 90 * 24 * time.Hour
+
+// apiTokenTouchInterval evita gravar last_used_at a cada requisição
+const apiTokenTouchInterval = time.Minute
+
+// APIToken é um token de longa duração para scripts; só o hash SHA-256 é guardado
+type APIToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Hash       string     `json:"-"`
+	Scopes     []Scope    `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type APITokenRepository interface {
+	CreateAPIToken(ctx context.Context, t *APIToken) error
+	// GetAPITokenByHash devolve o token mesmo revogado ou expirado
+	GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, error)
+	ListAPITokens(ctx context.Context, user uuid.UUID) ([]APIToken, error)
+	// RevokeAPIToken marca o token do usuário como revogado; ErrNotFound se não existir
+	RevokeAPIToken(ctx context.Context, user, id uuid.UUID, at time.Time) error
+	TouchAPIToken(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// Principal é quem fez a requisição. Scopes nil indica sessão de login, sem restrição.
+type Principal struct {
+	UserID  uuid.UUID
+	TokenID *uuid.UUID
+	Scopes  []Scope
+}
+
+// IsSession indica autenticação por login (e não por token de API)
+func (p *Principal) IsSession() bool { return p.TokenID == nil }
+
+func (p *Principal) HasScope(s Scope) bool {
+	return p.IsSession() || slices.Contains(p.Scopes, s)
+}
+
+func hashAPIToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// APITokenInput descreve um novo token; ExpiresAt zero usa DefaultAPITokenTTL
+type APITokenInput struct {
+	Name      string
+	Scopes    []Scope
+	ExpiresAt time.Time
+}
+
+// CreateAPIToken emite um token para o usuário do contexto. O segredo é devolvido
+// apenas aqui; depois disso só o hash existe.
+func (s *Service) CreateAPIToken(ctx context.Context, in APITokenInput) (*APIToken, string, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now().UTC()
+	t := &APIToken{
+		ID:        uuid.New(),
+		UserID:    user,
+		Name:      strings.TrimSpace(in.Name),
+		ExpiresAt: in.ExpiresAt.UTC(),
+		CreatedAt: now,
+	}
+	if t.Name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", ErrBadRequest)
+	}
+	if len(in.Scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrBadRequest)
+	}
+	for _, sc := range in.Scopes {
+		if !sc.Valid() {
+			return nil, "", fmt.Errorf("%w: unknown scope %q", ErrBadRequest, sc)
+		}
+		if !slices.Contains(t.Scopes, sc) {
+			t.Scopes = append(t.Scopes, sc)
+		}
+	}
+	if in.ExpiresAt.IsZero() {
+		t.ExpiresAt = now.Add(DefaultAPITokenTTL)
+	} else if !t.ExpiresAt.After(now) {
+		return nil, "", fmt.Errorf("%w: expires_at must be in the future", ErrBadRequest)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	secret := APITokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	t.Hash = hashAPIToken(secret)
+	if err := s.repo.CreateAPIToken(ctx, t); err != nil {
+		return nil, "", err
+	}
+	return t, secret, nil
+}
+
+// ListAPITokens lista os tokens do usuário do contexto (sem os segredos)
+func (s *Service) ListAPITokens(ctx context.Context) ([]APIToken, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListAPITokens(ctx, user)
+}
+
+func (s *Service) RevokeAPIToken(ctx context.Context, id uuid.UUID) error {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	return s.repo.RevokeAPIToken(ctx, user, id, time.Now().UTC())
+}
+
+// authenticateAPIToken valida um token de API e registra o último uso
+func (s *Service) authenticateAPIToken(ctx context.Context, secret string, now time.Time) (*Principal, error) {
+	t, err := s.repo.GetAPITokenByHash(ctx, hashAPIToken(secret))
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.RevokedAt != nil || !now.Before(t.ExpiresAt) {
+		return nil, ErrUnauthorized
+	}
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= apiTokenTouchInterval {
+		if err := s.repo.TouchAPIToken(ctx, t.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return &Principal{UserID: t.UserID, TokenID: &t.ID, Scopes: t.Scopes}, nil
+}
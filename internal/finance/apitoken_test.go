@@ -0,0 +1,50 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestService_APITokens(t *testing.T) {
+	s := NewService(NewMemoryRepo())
+	u, _ := s.Register(context.Background(), "ana@example.com", "segredo-123")
+	ctx := WithUser(context.Background(), u.ID)
+
+	if _, _, err := s.CreateAPIToken(ctx, APITokenInput{Name: "cron", Scopes: []Scope{"admin"}}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("unknown scope: %v", err)
+	}
+	tok, secret, err := s.CreateAPIToken(ctx, APITokenInput{Name: "cron", Scopes: []Scope{ScopeTransactionsRead}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret, APITokenPrefix) || tok.Hash == secret || tok.Hash != hashAPIToken(secret) {
+		t.Errorf("secret must be stored hashed: %+v", tok)
+	}
+
+	p, err := s.Authenticate(context.Background(), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.UserID != u.ID || p.IsSession() || !p.HasScope(ScopeTransactionsRead) || p.HasScope(ScopeTransactionsWrite) {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+	ts, _ := s.ListAPITokens(ctx)
+	if len(ts) != 1 || ts[0].LastUsedAt == nil {
+		t.Errorf("last_used_at not recorded: %+v", ts)
+	}
+	if _, err := s.authenticateAPIToken(context.Background(), secret, tok.ExpiresAt); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expired token: %v", err)
+	}
+
+	if err := s.RevokeAPIToken(WithUser(context.Background(), tok.ID), tok.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("revoke by another user: %v", err)
+	}
+	if err := s.RevokeAPIToken(ctx, tok.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Authenticate(context.Background(), secret); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("revoked token: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxBatchSize limita a quantidade de itens por lote
+const MaxBatchSize = 5000
+
+// BatchResult é o resultado de um item do lote, na mesma posição da entrada
+type BatchResult struct {
+	Index       int          `json:"index"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Error       string       `json:"error,omitempty"`
+}
+
+// BatchOutcome resume o lote. Em modo atômico, qualquer item inválido impede a gravação
+// de todos; fora dele, os válidos são gravados e os inválidos reportados.
+type BatchOutcome struct {
+	Atomic  bool          `json:"atomic"`
+	Created int           `json:"created"`
+	Failed  int           `json:"failed"`
+	Results []BatchResult `json:"results"`
+}
+
+// CreateBatch valida cada item com as regras de Create e grava os válidos de uma vez
+func (s *Service) CreateBatch(ctx context.Context, ins []TxInput, atomic bool) (*BatchOutcome, error) {
+	if len(ins) == 0 || len(ins) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: batch must have between 1 and %d items", ErrBadRequest, MaxBatchSize)
+	}
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	out := &BatchOutcome{Atomic: atomic, Results: make([]BatchResult, len(ins))}
+	valid := make([]*Transaction, 0, len(ins))
+	for i, in := range ins {
+		out.Results[i].Index = i
+		tx, err := s.newTx(ctx, in, now, cats)
+		if err != nil {
+			out.Results[i].Error = err.Error()
+			out.Failed++
+			continue
+		}
+		out.Results[i].Transaction = tx
+		valid = append(valid, tx)
+	}
+	if atomic && out.Failed > 0 {
+		// nada é gravado: limpa as transações montadas para não sugerir persistência
+		for i := range out.Results {
+			out.Results[i].Transaction = nil
+		}
+		return out, nil
+	}
+	if len(valid) > 0 {
+		if err := s.saveCategories(ctx, cats, valid...); err != nil {
+			return nil, err
+		}
+		if err := s.repo.CreateBatch(ctx, valid); err != nil {
+			return nil, err
+		}
+	}
+	out.Created = len(valid)
+	return out, nil
+}
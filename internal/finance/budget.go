@@ -0,0 +1,297 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Budget é o limite de gastos de uma categoria em um mês (Month = "YYYY-MM") ou em todos os
+// meses (Month vazio). O orçamento de um mês específico prevalece sobre o recorrente e o
+// de uma categoria inclui as despesas das subcategorias. Valores na moeda base.
+type Budget struct {
+	ID         uuid.UUID `json:"id"`
+	LedgerID   uuid.UUID `json:"-"`
+	CategoryID uuid.UUID `json:"category_id"`
+	Category   string    `json:"category"` // caminho da categoria, preenchido pelo Service
+	Month      string    `json:"month,omitempty"`
+	LimitCents int64     `json:"limit_cents"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (b *Budget) Recurring() bool { return b.Month == "" }
+
+type BudgetRepository interface {
+	// CreateBudget falha com ErrConflict se já houver orçamento da categoria no mesmo mês
+	// (ou recorrente)
+	CreateBudget(ctx context.Context, b *Budget) error
+	GetBudget(ctx context.Context, id uuid.UUID) (*Budget, error)
+	ListBudgets(ctx context.Context) ([]Budget, error)
+	UpdateBudget(ctx context.Context, b *Budget) error
+	DeleteBudget(ctx context.Context, id uuid.UUID) error
+}
+
+type BudgetInput struct {
+	Category   string // nome, apelido ou caminho de uma categoria existente
+	Month      string // YYYY-MM; vazio = recorrente
+	LimitCents int64
+}
+
+type BudgetPatch struct {
+	Category   *string
+	Month      *string
+	LimitCents *int64
+}
+
+// BudgetStatus compara o orçamento com as despesas do mês
+type BudgetStatus struct {
+	BudgetID       uuid.UUID `json:"budget_id"`
+	CategoryID     uuid.UUID `json:"category_id"`
+	Category       string    `json:"category"`
+	Recurring      bool      `json:"recurring"`
+	LimitCents     int64     `json:"limit_cents"`
+	SpentCents     int64     `json:"spent_cents"`
+	RemainingCents int64     `json:"remaining_cents"` // negativo quando estourado
+	PercentUsed    float64   `json:"percent_used"`
+	ProjectedCents int64     `json:"projected_cents"` // gasto previsto no fim do mês pelo ritmo diário
+	Overspent      bool      `json:"overspent"`
+	WillOverspend  bool      `json:"will_overspend"`
+}
+
+type BudgetReport struct {
+	Year        int            `json:"year"`
+	Month       int            `json:"month"`
+	Currency    string         `json:"currency"`
+	DaysElapsed int            `json:"days_elapsed"`
+	DaysInMonth int            `json:"days_in_month"`
+	Budgets     []BudgetStatus `json:"budgets"`
+}
+
+func normalizeBudgetMonth(m string) (string, error) {
+	m = strings.TrimSpace(m)
+	if m == "" {
+		return "", nil
+	}
+	t, err := time.Parse("2006-01", m)
+	if err != nil {
+		return "", fmt.Errorf("%w: month must be YYYY-MM", ErrBadRequest)
+	}
+	return t.Format("2006-01"), nil
+}
+
+// resolveBudget valida o orçamento e aponta CategoryID para a categoria informada
+func (cs *categorySet) resolveBudget(b *Budget, category string) error {
+	if b.LimitCents <= 0 {
+		return fmt.Errorf("%w: limit_cents must be positive", ErrBadRequest)
+	}
+	if category != "" {
+		c, ok := cs.byKey[categoryKey(category)]
+		if !ok {
+			return fmt.Errorf("%w: unknown category %q", ErrBadRequest, strings.TrimSpace(category))
+		}
+		b.CategoryID = c.ID
+	}
+	if c, ok := cs.byID[b.CategoryID]; ok {
+		b.Category = c.Path
+	}
+	return nil
+}
+
+func (s *Service) CreateBudget(ctx context.Context, in BudgetInput) (*Budget, error) {
+	if strings.TrimSpace(in.Category) == "" {
+		return nil, fmt.Errorf("%w: category is required", ErrBadRequest)
+	}
+	month, err := normalizeBudgetMonth(in.Month)
+	if err != nil {
+		return nil, err
+	}
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	b := &Budget{
+		ID:         uuid.New(),
+		LedgerID:   ledgerScope(ctx),
+		Month:      month,
+		LimitCents: in.LimitCents,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := cats.resolveBudget(b, in.Category); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateBudget(ctx, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ListBudgets lista os orçamentos do livro por categoria e mês (recorrentes primeiro)
+func (s *Service) ListBudgets(ctx context.Context) ([]Budget, error) {
+	bs, err := s.repo.ListBudgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range bs {
+		if c, ok := cats.byID[bs[i].CategoryID]; ok {
+			bs[i].Category = c.Path
+		}
+	}
+	slices.SortFunc(bs, func(a, b Budget) int {
+		if c := strings.Compare(categoryKey(a.Category), categoryKey(b.Category)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Month, b.Month)
+	})
+	return bs, nil
+}
+
+func (s *Service) GetBudget(ctx context.Context, id uuid.UUID) (*Budget, error) {
+	b, err := s.repo.GetBudget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := cats.byID[b.CategoryID]; ok {
+		b.Category = c.Path
+	}
+	return b, nil
+}
+
+func (s *Service) UpdateBudget(ctx context.Context, id uuid.UUID, p BudgetPatch) (*Budget, error) {
+	b, err := s.repo.GetBudget(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Month != nil {
+		if b.Month, err = normalizeBudgetMonth(*p.Month); err != nil {
+			return nil, err
+		}
+	}
+	if p.LimitCents != nil {
+		b.LimitCents = *p.LimitCents
+	}
+	category := ""
+	if p.Category != nil {
+		if category = *p.Category; strings.TrimSpace(category) == "" {
+			return nil, fmt.Errorf("%w: category is required", ErrBadRequest)
+		}
+	}
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cats.resolveBudget(b, category); err != nil {
+		return nil, err
+	}
+	b.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateBudget(ctx, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (s *Service) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteBudget(ctx, id)
+}
+
+// BudgetStatus compara os orçamentos vigentes no mês com as despesas (na moeda base). A
+// projeção extrapola o gasto médio diário até o fim do mês; meses encerrados projetam o
+// próprio gasto e meses futuros, zero.
+func (s *Service) BudgetStatus(ctx context.Context, year, month int) (*BudgetReport, error) {
+	if month < 1 || month > 12 {
+		return nil, ErrBadRequest
+	}
+	budgets, err := s.repo.ListBudgets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%04d-%02d", year, month)
+	active := map[uuid.UUID]Budget{} // por categoria
+	for _, b := range budgets {
+		if b.Month != key && !b.Recurring() {
+			continue
+		}
+		if cur, ok := active[b.CategoryID]; !ok || cur.Recurring() {
+			active[b.CategoryID] = b
+		}
+	}
+
+	sum, err := s.MonthlySummary(ctx, year, month, TxFilter{Type: Expense}, "")
+	if err != nil {
+		return nil, err
+	}
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// gasto por categoria, acumulado também nos ancestrais
+	spent := map[uuid.UUID]int64{}
+	for _, ct := range sum.Categories {
+		c, ok := cats.byKey[categoryKey(ct.Category)]
+		for depth := 0; ok && depth < 32; depth++ {
+			spent[c.ID] += ct.TotalCents
+			if c.ParentID == nil {
+				break
+			}
+			c, ok = cats.byID[*c.ParentID]
+		}
+	}
+
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	days := start.AddDate(0, 1, -1).Day()
+	elapsed := days
+	if now := time.Now().UTC(); now.Before(start) {
+		elapsed = 0
+	} else if now.Year() == year && int(now.Month()) == month {
+		elapsed = now.Day()
+	}
+
+	rep := &BudgetReport{
+		Year:        year,
+		Month:       month,
+		Currency:    sum.Currency,
+		DaysElapsed: elapsed,
+		DaysInMonth: days,
+		Budgets:     []BudgetStatus{},
+	}
+	for _, b := range active {
+		st := BudgetStatus{
+			BudgetID:       b.ID,
+			CategoryID:     b.CategoryID,
+			Recurring:      b.Recurring(),
+			LimitCents:     b.LimitCents,
+			SpentCents:     spent[b.CategoryID],
+			ProjectedCents: spent[b.CategoryID],
+		}
+		if c, ok := cats.byID[b.CategoryID]; ok {
+			st.Category = c.Path
+		}
+		st.RemainingCents = st.LimitCents - st.SpentCents
+		st.PercentUsed = math.Round(float64(st.SpentCents)*10000/float64(st.LimitCents)) / 100
+		if elapsed > 0 && elapsed < days {
+			st.ProjectedCents = st.SpentCents * int64(days) / int64(elapsed)
+		}
+		st.Overspent = st.SpentCents > st.LimitCents
+		st.WillOverspend = st.ProjectedCents > st.LimitCents
+		rep.Budgets = append(rep.Budgets, st)
+	}
+	slices.SortFunc(rep.Budgets, func(a, b BudgetStatus) int {
+		return strings.Compare(categoryKey(a.Category), categoryKey(b.Category))
+	})
+	return rep, nil
+}
@@ -0,0 +1,68 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_BudgetStatus(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	at := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, in := range []TxInput{
+		{Type: Expense, Category: "Moradia > Aluguel", AmountCents: 150000},
+		{Type: Expense, Category: "Moradia > Luz", AmountCents: 30000},
+		{Type: Expense, Category: "Mercado", AmountCents: 90000},
+		{Type: Income, Category: "Salário", AmountCents: 500000},
+	} {
+		in.OccurredAt = at
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := s.CreateBudget(ctx, BudgetInput{Category: "Moradia", LimitCents: 200000}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateBudget(ctx, BudgetInput{Category: "moradia", LimitCents: 1}); !errors.Is(err, ErrConflict) {
+		t.Errorf("duplicate recurring budget: %v", err)
+	}
+	if _, err := s.CreateBudget(ctx, BudgetInput{Category: "Mercado", LimitCents: 100000}); err != nil {
+		t.Fatal(err)
+	}
+	// o orçamento de março prevalece sobre o recorrente
+	if _, err := s.CreateBudget(ctx, BudgetInput{Category: "Mercado", Month: "2025-03", LimitCents: 80000}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateBudget(ctx, BudgetInput{Category: "Lazer", LimitCents: 1000}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("budget for unknown category: %v", err)
+	}
+	if _, err := s.CreateBudget(ctx, BudgetInput{Category: "Mercado", Month: "03/2025", LimitCents: 1000}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("invalid month: %v", err)
+	}
+
+	rep, err := s.BudgetStatus(ctx, 2025, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.DaysElapsed != 31 || len(rep.Budgets) != 2 {
+		t.Fatalf("unexpected report: %+v", rep)
+	}
+	mercado, moradia := rep.Budgets[0], rep.Budgets[1]
+	if mercado.Recurring || mercado.SpentCents != 90000 || mercado.RemainingCents != -10000 ||
+		mercado.PercentUsed != 112.5 || !mercado.Overspent || mercado.ProjectedCents != 90000 {
+		t.Errorf("mercado: %+v", mercado)
+	}
+	// subcategorias entram no orçamento da categoria pai
+	if moradia.Category != "Moradia" || moradia.SpentCents != 180000 || moradia.PercentUsed != 90 || moradia.Overspent {
+		t.Errorf("moradia: %+v", moradia)
+	}
+
+	// mês futuro: nada gasto nem projetado
+	future, err := s.BudgetStatus(ctx, 2099, 1)
+	if err != nil || future.DaysElapsed != 0 || len(future.Budgets) != 2 || future.Budgets[0].LimitCents != 100000 {
+		t.Errorf("future: %+v %v", future, err)
+	}
+}
@@ -0,0 +1,394 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// CategoryPathSep separa os níveis no caminho de uma categoria, ex: "Moradia > Aluguel"
+const CategoryPathSep = " > "
+
+// Category é uma categoria gerenciada do livro. Transações guardam o nome canônico;
+// nomes e apelidos são únicos no livro sem diferenciar maiúsculas e espaços extras.
+type Category struct {
+	ID        uuid.UUID  `json:"id"`
+	LedgerID  uuid.UUID  `json:"-"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	Name      string     `json:"name"`
+	Path      string     `json:"path"` // nomes desde a raiz, preenchido pelo Service
+	Aliases   []string   `json:"aliases"`
+	Color     string     `json:"color,omitempty"` // #RRGGBB
+	Icon      string     `json:"icon,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type CategoryRepository interface {
+	// CreateCategory falha com ErrConflict se o livro já tiver categoria com o mesmo nome
+	CreateCategory(ctx context.Context, c *Category) error
+	GetCategory(ctx context.Context, id uuid.UUID) (*Category, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	// UpdateCategory grava c; se o nome mudou, as transações e regras recorrentes com oldName
+	// passam para o novo
+	UpdateCategory(ctx context.Context, c *Category, oldName string) error
+	// DeleteCategory falha com ErrConflict se houver subcategorias, transações ou regras
+	// recorrentes na categoria; os orçamentos da categoria são removidos junto
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+}
+
+type CategoryInput struct {
+	Name     string
+	ParentID *uuid.UUID
+	Aliases  []string
+	Color    string
+	Icon     string
+}
+
+// CategoryPatch descreve uma alteração parcial; ParentID apontando para uuid.Nil move a
+// categoria para a raiz
+type CategoryPatch struct {
+	Name     *string
+	ParentID *uuid.UUID
+	Aliases  *[]string
+	Color    *string
+	Icon     *string
+}
+
+// WithCategoryAutoCreate faz com que transações com categoria desconhecida criem a
+// categoria (e os níveis do caminho) em vez de falhar com ErrBadRequest
+func WithCategoryAutoCreate(on bool) Option {
+	return func(s *Service) { s.autoCreateCategories = on }
+}
+
+// categoryKey normaliza nomes e caminhos para comparação: minúsculas, espaços colapsados
+// e níveis separados por ">"
+func categoryKey(s string) string {
+	parts := strings.Split(s, ">")
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(p), " "))
+	}
+	return strings.Join(parts, ">")
+}
+
+// cleanCategoryName colapsa espaços e rejeita nomes vazios ou com o separador de níveis
+func cleanCategoryName(s, field string) (string, error) {
+	s = strings.Join(strings.Fields(s), " ")
+	switch {
+	case s == "":
+		return "", fmt.Errorf("%w: %s is required", ErrBadRequest, field)
+	case strings.Contains(s, ">"):
+		return "", fmt.Errorf("%w: %s must not contain '>'", ErrBadRequest, field)
+	case utf8.RuneCountInString(s) > 64:
+		return "", fmt.Errorf("%w: %s must have at most 64 characters", ErrBadRequest, field)
+	}
+	return s, nil
+}
+
+func validColor(c string) bool {
+	if len(c) != 7 || c[0] != '#' {
+		return false
+	}
+	for _, r := range c[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeCategory(c *Category) error {
+	var err error
+	if c.Name, err = cleanCategoryName(c.Name, "name"); err != nil {
+		return err
+	}
+	aliases := []string{}
+	seen := map[string]bool{categoryKey(c.Name): true}
+	for _, a := range c.Aliases {
+		if a, err = cleanCategoryName(a, "alias"); err != nil {
+			return err
+		}
+		if k := categoryKey(a); !seen[k] {
+			seen[k] = true
+			aliases = append(aliases, a)
+		}
+	}
+	c.Aliases = aliases
+	c.Color = strings.TrimSpace(c.Color)
+	if c.Color != "" && !validColor(c.Color) {
+		return fmt.Errorf("%w: color must be #RRGGBB", ErrBadRequest)
+	}
+	c.Icon = strings.TrimSpace(c.Icon)
+	if utf8.RuneCountInString(c.Icon) > 32 {
+		return fmt.Errorf("%w: icon must have at most 32 characters", ErrBadRequest)
+	}
+	return nil
+}
+
+// categorySet indexa as categorias do livro por nome, apelido e caminho. É carregado uma
+// vez por operação; categorias criadas no modo auto-create ficam pendentes até saveCategories.
+type categorySet struct {
+	ledger     uuid.UUID
+	autoCreate bool
+	byID       map[uuid.UUID]*Category
+	byKey      map[string]*Category
+	pending    []*Category
+}
+
+func newCategorySet(ledger uuid.UUID, cats []Category) *categorySet {
+	cs := &categorySet{ledger: ledger, byID: map[uuid.UUID]*Category{}, byKey: map[string]*Category{}}
+	for i := range cats {
+		cs.byID[cats[i].ID] = &cats[i]
+	}
+	for _, c := range cs.byID {
+		c.Path = cs.path(c)
+		cs.index(c)
+	}
+	return cs
+}
+
+func (cs *categorySet) index(c *Category) {
+	cs.byKey[categoryKey(c.Name)] = c
+	for _, a := range c.Aliases {
+		cs.byKey[categoryKey(a)] = c
+	}
+	if c.ParentID != nil {
+		cs.byKey[categoryKey(c.Path)] = c
+	}
+}
+
+// path monta o caminho desde a raiz; o limite evita laço em dados inconsistentes
+func (cs *categorySet) path(c *Category) string {
+	names := []string{c.Name}
+	for p := c.ParentID; p != nil && len(names) < 32; {
+		parent, ok := cs.byID[*p]
+		if !ok {
+			break
+		}
+		names = append(names, parent.Name)
+		p = parent.ParentID
+	}
+	slices.Reverse(names)
+	return strings.Join(names, CategoryPathSep)
+}
+
+// sorted devolve as categorias ordenadas pelo caminho
+func (cs *categorySet) sorted() []Category {
+	out := make([]Category, 0, len(cs.byID))
+	for _, c := range cs.byID {
+		out = append(out, *c)
+	}
+	slices.SortFunc(out, func(a, b Category) int {
+		return strings.Compare(categoryKey(a.Path), categoryKey(b.Path))
+	})
+	return out
+}
+
+// resolve devolve o nome canônico da categoria informada por nome, apelido ou caminho
+func (cs *categorySet) resolve(name string, now time.Time) (string, error) {
+	if c, ok := cs.byKey[categoryKey(name)]; ok {
+		return c.Name, nil
+	}
+	if !cs.autoCreate {
+		return "", fmt.Errorf("%w: unknown category %q", ErrBadRequest, strings.TrimSpace(name))
+	}
+	var parent *Category
+	for _, part := range strings.Split(name, ">") {
+		part, err := cleanCategoryName(part, "category")
+		if err != nil {
+			return "", err
+		}
+		key := categoryKey(part)
+		if parent != nil {
+			key = categoryKey(parent.Path + ">" + part)
+		}
+		c, ok := cs.byKey[key]
+		if !ok {
+			if _, taken := cs.byKey[categoryKey(part)]; taken {
+				return "", fmt.Errorf("%w: category %q already exists elsewhere", ErrConflict, part)
+			}
+			c = &Category{ID: uuid.New(), LedgerID: cs.ledger, Name: part, Aliases: []string{}, CreatedAt: now, UpdatedAt: now}
+			if parent != nil {
+				c.ParentID = &parent.ID
+			}
+			cs.byID[c.ID] = c
+			c.Path = cs.path(c)
+			cs.index(c)
+			cs.pending = append(cs.pending, c)
+		}
+		parent = c
+	}
+	return parent.Name, nil
+}
+
+func (s *Service) loadCategories(ctx context.Context) (*categorySet, error) {
+	cats, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cs := newCategorySet(ledgerScope(ctx), cats)
+	cs.autoCreate = s.autoCreateCategories
+	return cs, nil
+}
+
+// saveCategories grava as categorias pendentes usadas por txs ou pelo rateio (e seus
+// ancestrais)
+func (s *Service) saveCategories(ctx context.Context, cs *categorySet, txs ...*Transaction) error {
+	if len(cs.pending) == 0 {
+		return nil
+	}
+	needed := map[uuid.UUID]bool{}
+	for _, t := range txs {
+		for _, line := range t.categoryLines() {
+			for c := cs.byKey[categoryKey(line.Category)]; c != nil && !needed[c.ID]; {
+				needed[c.ID] = true
+				if c.ParentID == nil {
+					break
+				}
+				c = cs.byID[*c.ParentID]
+			}
+		}
+	}
+	rest := cs.pending[:0]
+	for _, c := range cs.pending {
+		if !needed[c.ID] {
+			rest = append(rest, c)
+			continue
+		}
+		if err := s.repo.CreateCategory(ctx, c); err != nil {
+			return err
+		}
+	}
+	cs.pending = rest
+	return nil
+}
+
+// checkCategory valida c contra as demais categorias do livro: nomes e apelidos únicos,
+// pai existente e sem ciclos
+func (cs *categorySet) checkCategory(c *Category) error {
+	keys := append([]string{c.Name}, c.Aliases...)
+	for _, k := range keys {
+		if other, ok := cs.byKey[categoryKey(k)]; ok && other.ID != c.ID {
+			return fmt.Errorf("%w: %q is already used by category %q", ErrConflict, k, other.Name)
+		}
+	}
+	for p, depth := c.ParentID, 0; p != nil; depth++ {
+		if *p == c.ID || depth > 32 {
+			return fmt.Errorf("%w: category cannot be its own ancestor", ErrBadRequest)
+		}
+		parent, ok := cs.byID[*p]
+		if !ok {
+			return fmt.Errorf("%w: parent category not found", ErrBadRequest)
+		}
+		p = parent.ParentID
+	}
+	return nil
+}
+
+func (s *Service) CreateCategory(ctx context.Context, in CategoryInput) (*Category, error) {
+	now := time.Now().UTC()
+	c := &Category{
+		ID:        uuid.New(),
+		LedgerID:  ledgerScope(ctx),
+		ParentID:  in.ParentID,
+		Name:      in.Name,
+		Aliases:   in.Aliases,
+		Color:     in.Color,
+		Icon:      in.Icon,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := normalizeCategory(c); err != nil {
+		return nil, err
+	}
+	cs, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.checkCategory(c); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateCategory(ctx, c); err != nil {
+		return nil, err
+	}
+	cs.byID[c.ID] = c
+	c.Path = cs.path(c)
+	return c, nil
+}
+
+// ListCategories lista as categorias do livro ordenadas pelo caminho
+func (s *Service) ListCategories(ctx context.Context) ([]Category, error) {
+	cs, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cs.sorted(), nil
+}
+
+func (s *Service) GetCategory(ctx context.Context, id uuid.UUID) (*Category, error) {
+	cs, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := cs.byID[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return c, nil
+}
+
+// UpdateCategory aplica o patch; renomear atualiza as transações da categoria
+func (s *Service) UpdateCategory(ctx context.Context, id uuid.UUID, p CategoryPatch) (*Category, error) {
+	cs, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cur, ok := cs.byID[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	c := *cur
+	oldName := c.Name
+	if p.Name != nil {
+		c.Name = *p.Name
+	}
+	if p.ParentID != nil {
+		c.ParentID = p.ParentID
+		if *p.ParentID == uuid.Nil {
+			c.ParentID = nil
+		}
+	}
+	if p.Aliases != nil {
+		c.Aliases = *p.Aliases
+	}
+	if p.Color != nil {
+		c.Color = *p.Color
+	}
+	if p.Icon != nil {
+		c.Icon = *p.Icon
+	}
+	if err := normalizeCategory(&c); err != nil {
+		return nil, err
+	}
+	if err := cs.checkCategory(&c); err != nil {
+		return nil, err
+	}
+	c.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateCategory(ctx, &c, oldName); err != nil {
+		return nil, err
+	}
+	cs.byID[c.ID] = &c
+	c.Path = cs.path(&c)
+	return &c, nil
+}
+
+// DeleteCategory falha com ErrConflict se a categoria tiver subcategorias ou transações; os
+// orçamentos da categoria são removidos junto
+func (s *Service) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteCategory(ctx, id)
+}
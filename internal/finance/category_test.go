@@ -0,0 +1,93 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestService_Categories(t *testing.T) {
+	s := NewService(NewMemoryRepo())
+	ctx := context.Background()
+
+	housing, err := s.CreateCategory(ctx, CategoryInput{Name: " Moradia ", Color: "#AA3300"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rent, err := s.CreateCategory(ctx, CategoryInput{Name: "Aluguel", ParentID: &housing.ID, Aliases: []string{"rent", "RENT "}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rent.Path != "Moradia > Aluguel" || len(rent.Aliases) != 1 {
+		t.Errorf("unexpected category: %+v", rent)
+	}
+	if _, err := s.CreateCategory(ctx, CategoryInput{Name: "Rent"}); !errors.Is(err, ErrConflict) {
+		t.Errorf("name clashing with alias: %v", err)
+	}
+	if _, err := s.CreateCategory(ctx, CategoryInput{Name: "x", Color: "red"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("invalid color: %v", err)
+	}
+	if _, err := s.UpdateCategory(ctx, housing.ID, CategoryPatch{ParentID: &rent.ID}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("cycle accepted: %v", err)
+	}
+
+	// nome, apelido e caminho resolvem para o nome canônico; desconhecidas falham
+	for _, in := range []string{"aluguel", " Rent", "moradia>aluguel"} {
+		tx, err := s.Create(ctx, TxInput{Type: Expense, Category: in, AmountCents: 1000})
+		if err != nil || tx.Category != "Aluguel" {
+			t.Errorf("%q: %+v %v", in, tx, err)
+		}
+	}
+	if _, err := s.Create(ctx, TxInput{Type: Expense, Category: "food", AmountCents: 1000}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("unknown category accepted: %v", err)
+	}
+
+	// renomear leva as transações junto; com transações ou filhas não remove
+	name := "Aluguel residencial"
+	if _, err := s.UpdateCategory(ctx, rent.ID, CategoryPatch{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	got, _ := s.ListByPeriod(ctx, now.Add(-time.Hour), now.Add(time.Hour), TxFilter{Category: name})
+	if len(got) != 3 {
+		t.Errorf("renamed transactions: %d", len(got))
+	}
+	if err := s.DeleteCategory(ctx, housing.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("delete parent: %v", err)
+	}
+	if err := s.DeleteCategory(ctx, rent.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("delete used: %v", err)
+	}
+	root := uuid.Nil
+	if c, err := s.UpdateCategory(ctx, rent.ID, CategoryPatch{ParentID: &root}); err != nil || c.ParentID != nil || c.Path != name {
+		t.Errorf("move to root: %+v %v", c, err)
+	}
+	if err := s.DeleteCategory(ctx, housing.ID); err != nil {
+		t.Errorf("delete empty: %v", err)
+	}
+}
+
+func TestService_CategoryAutoCreate(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+
+	if _, err := s.Create(ctx, TxInput{Type: Expense, Category: "Transporte > Uber", AmountCents: 1500}); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := s.Create(ctx, TxInput{Type: Expense, Category: "uber", AmountCents: 900})
+	if err != nil || tx.Category != "Uber" {
+		t.Errorf("existing auto-created category: %+v %v", tx, err)
+	}
+	// itens inválidos do lote não criam categorias
+	res, err := s.CreateBatch(ctx, []TxInput{{Type: Expense, Category: "Lazer", AmountCents: -1}}, false)
+	if err != nil || res.Failed != 1 {
+		t.Fatalf("batch: %+v %v", res, err)
+	}
+	cats, _ := s.ListCategories(ctx)
+	if len(cats) != 2 || cats[0].Path != "Transporte" || cats[1].Path != "Transporte > Uber" {
+		t.Errorf("categories: %+v", cats)
+	}
+}
@@ -0,0 +1,155 @@
+package finance
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DateRange é um período fechado (From e To inclusive)
+type DateRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// monthRange devolve o mês civil; meses fora de 1..12 são normalizados (0 = dezembro do
+// ano anterior)
+func monthRange(year, month int) DateRange {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return DateRange{From: from, To: from.AddDate(0, 1, 0).Add(-time.Microsecond)}
+}
+
+// Delta compara um valor do período atual com o do período de referência
+type Delta struct {
+	Current  int64    `json:"current_cents"`
+	Previous int64    `json:"previous_cents"`
+	Change   int64    `json:"change_cents"`
+	Percent  *float64 `json:"change_percent"` // sobre o valor de referência; nil se ele é zero
+}
+
+func newDelta(cur, prev int64) Delta {
+	d := Delta{Current: cur, Previous: prev, Change: cur - prev}
+	if prev != 0 {
+		p := math.Round(float64(d.Change)*10000/math.Abs(float64(prev))) / 100
+		d.Percent = &p
+	}
+	return d
+}
+
+// CategoryDelta é a variação de uma categoria dentro de um tipo
+type CategoryDelta struct {
+	Type     TxType `json:"type"`
+	Category string `json:"category"`
+	Path     string `json:"path,omitempty"`
+	Delta
+}
+
+// Comparison compara os totais de dois períodos
+type Comparison struct {
+	Currency   string          `json:"currency"` // moeda base da conversão
+	Current    DateRange       `json:"current"`
+	Previous   DateRange       `json:"previous"`
+	Income     Delta           `json:"income"`
+	Expense    Delta           `json:"expense"`
+	Net        Delta           `json:"net"`
+	Categories []CategoryDelta `json:"categories"` // receitas e depois despesas, maiores variações primeiro
+}
+
+// Compare calcula a variação de receitas, despesas, saldo e de cada categoria do período
+// "prev" para "cur", na moeda "base" (vazio = moeda base)
+func (s *Service) Compare(ctx context.Context, cur, prev DateRange, f TxFilter, base string) (*Comparison, error) {
+	// um intervalo por ano: só os totais e as categorias interessam
+	now, err := s.RangeSummary(ctx, cur.From, cur.To, GranularityYear, f, base)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.RangeSummary(ctx, prev.From, prev.To, GranularityYear, f, base)
+	if err != nil {
+		return nil, err
+	}
+	c := &Comparison{
+		Currency: now.Currency,
+		Current:  DateRange{From: now.From, To: now.To},
+		Previous: DateRange{From: before.From, To: before.To},
+		Income:   newDelta(now.Income, before.Income),
+		Expense:  newDelta(now.Expense, before.Expense),
+		Net:      newDelta(now.Net, before.Net),
+	}
+	totals := map[catKey]*[2]int64{}
+	paths := map[string]string{}
+	for i, rs := range []*RangeSummary{now, before} {
+		for _, ct := range rs.Categories {
+			k := catKey{ct.Type, ct.Category}
+			if totals[k] == nil {
+				totals[k] = &[2]int64{}
+			}
+			totals[k][i] = ct.TotalCents
+			paths[ct.Category] = ct.Path
+		}
+	}
+	c.Categories = make([]CategoryDelta, 0, len(totals))
+	for k, v := range totals {
+		c.Categories = append(c.Categories, CategoryDelta{
+			Type: k.typ, Category: k.category, Path: paths[k.category], Delta: newDelta(v[0], v[1]),
+		})
+	}
+	slices.SortFunc(c.Categories, func(a, b CategoryDelta) int {
+		if a.Type != b.Type {
+			return strings.Compare(string(b.Type), string(a.Type)) // income antes de expense
+		}
+		if x, y := abs(a.Change), abs(b.Change); x != y {
+			return cmp.Compare(y, x)
+		}
+		return strings.Compare(a.Category, b.Category)
+	})
+	return c, nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func formatPercent(p *float64) string {
+	if p == nil {
+		return "n/d"
+	}
+	return fmt.Sprintf("%+.1f%%", *p)
+}
+
+// comparisonSection monta a seção do relatório mensal que compara o mês com o anterior e
+// com o mesmo mês do ano anterior; sem cotação para os meses de referência, a seção avisa
+// em vez de falhar o relatório
+func (s *Service) comparisonSection(ctx context.Context, year, month int, base string) (string, error) {
+	var b strings.Builder
+	cur := monthRange(year, month)
+	for _, ref := range []DateRange{monthRange(year, month-1), monthRange(year-1, month)} {
+		fmt.Fprintf(&b, "COMPARATIVO COM %s/%d:\n", strings.ToUpper(monthNames[ref.From.Month()]), ref.From.Year())
+		b.WriteString("------------------------------------------\n")
+		c, err := s.Compare(ctx, cur, ref, TxFilter{}, base)
+		if errors.Is(err, ErrMissingRate) {
+			b.WriteString("Indisponível: faltam cotações do período\n")
+			b.WriteString("------------------------------------------\n\n")
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "%-15s %14s %9s\n", "", "Valor", "Variação")
+		for _, row := range []struct {
+			label string
+			d     Delta
+		}{{"Receitas:", c.Income}, {"Despesas:", c.Expense}, {"Saldo:", c.Net}} {
+			fmt.Fprintf(&b, "%-15s %14s %9s\n", row.label, formatMoney(row.d.Previous, c.Currency), formatPercent(row.d.Percent))
+		}
+		b.WriteString("------------------------------------------\n\n")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,56 @@
+package finance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_Compare(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	for _, in := range []TxInput{
+		{Type: Income, Category: "Salário", AmountCents: 500000, OccurredAt: time.Date(2025, 9, 5, 12, 0, 0, 0, time.UTC)},
+		{Type: Expense, Category: "Mercado", AmountCents: 100000, OccurredAt: time.Date(2025, 9, 10, 12, 0, 0, 0, time.UTC)},
+		{Type: Income, Category: "Salário", AmountCents: 500000, OccurredAt: time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)},
+		{Type: Expense, Category: "Mercado", AmountCents: 125000, OccurredAt: time.Date(2025, 10, 10, 12, 0, 0, 0, time.UTC)},
+		{Type: Expense, Category: "Viagem", AmountCents: 50000, OccurredAt: time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)},
+	} {
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := s.Compare(ctx, monthRange(2025, 10), monthRange(2025, 9), TxFilter{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Income.Change != 0 || *c.Income.Percent != 0 {
+		t.Errorf("income: %+v", c.Income)
+	}
+	if c.Expense.Change != 75000 || *c.Expense.Percent != 75 {
+		t.Errorf("expense: %+v", c.Expense)
+	}
+	if c.Net.Current != 325000 || c.Net.Previous != 400000 || *c.Net.Percent != -18.75 {
+		t.Errorf("net: %+v", c.Net)
+	}
+	if len(c.Categories) != 3 || c.Categories[0].Category != "Salário" || c.Categories[1].Category != "Viagem" {
+		t.Fatalf("categories: %+v", c.Categories)
+	}
+	if v := c.Categories[1]; v.Previous != 0 || v.Percent != nil {
+		t.Errorf("new category: %+v", v)
+	}
+	if m := c.Categories[2]; m.Change != 25000 || *m.Percent != 25 {
+		t.Errorf("mercado: %+v", m)
+	}
+
+	report, err := s.GenerateMonthlyReport(ctx, 2025, 10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report, "COMPARATIVO COM SETEMBRO/2025") || !strings.Contains(report, "+75.0%") ||
+		!strings.Contains(report, "COMPARATIVO COM OUTUBRO/2024") || !strings.Contains(report, "n/d") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
@@ -0,0 +1,153 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// ErrMissingRate indica que não há cotação para converter uma transação na moeda base
+var ErrMissingRate = errors.New("missing exchange rate")
+
+// ExchangeRate diz quanto 1 unidade de From vale em To a partir de Date (cotação diária)
+type ExchangeRate struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Date time.Time `json:"date"`
+	Rate float64   `json:"rate"`
+}
+
+// RateRepository guarda as cotações de cada livro: quem lança em moeda estrangeira informa
+// as próprias cotações, que não afetam os resumos de outros livros
+type RateRepository interface {
+	// UpsertRates grava as cotações, substituindo as existentes para o mesmo par e dia
+	UpsertRates(ctx context.Context, rates []ExchangeRate) error
+	// ListRates lista as cotações; from/to vazios não filtram
+	ListRates(ctx context.Context, from, to string) ([]ExchangeRate, error)
+}
+
+// WithBaseCurrency define a moeda padrão de transações sem conta e dos resumos
+func WithBaseCurrency(c string) Option {
+	return func(s *Service) {
+		if cur, ok := normalizeCurrency(c); ok {
+			s.baseCurrency = cur
+		}
+	}
+}
+
+// BaseCurrency devolve a moeda padrão configurada
+func (s *Service) BaseCurrency() string { return s.baseCurrency }
+
+// resolveBase normaliza a moeda pedida pelo cliente, caindo na moeda base do serviço
+func (s *Service) resolveBase(c string) (string, error) {
+	if c == "" {
+		return s.baseCurrency, nil
+	}
+	cur, ok := normalizeCurrency(c)
+	if !ok {
+		return "", fmt.Errorf("%w: invalid currency %q", ErrBadRequest, c)
+	}
+	return cur, nil
+}
+
+// ImportRates valida e grava um lote de cotações no livro do contexto
+func (s *Service) ImportRates(ctx context.Context, rates []ExchangeRate) error {
+	if len(rates) == 0 {
+		return ErrBadRequest
+	}
+	for i := range rates {
+		r := &rates[i]
+		from, ok1 := normalizeCurrency(r.From)
+		to, ok2 := normalizeCurrency(r.To)
+		if !ok1 || !ok2 || from == to || r.Date.IsZero() || !(r.Rate > 0) || math.IsInf(r.Rate, 0) {
+			return fmt.Errorf("%w: invalid rate at index %d", ErrBadRequest, i)
+		}
+		r.From, r.To = from, to
+		r.Date = rateDay(r.Date)
+	}
+	return s.repo.UpsertRates(ctx, rates)
+}
+
+func (s *Service) ListRates(ctx context.Context, from, to string) ([]ExchangeRate, error) {
+	return s.repo.ListRates(ctx, from, to)
+}
+
+// rateBook indexa as cotações por par, ordenadas por data
+type rateBook map[ratePair][]ExchangeRate
+
+// loadRates carrega todas as cotações do livro para conversões feitas no próprio Service
+func (s *Service) loadRates(ctx context.Context) (rateBook, error) {
+	rates, err := s.repo.ListRates(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+	rb := rateBook{}
+	for _, r := range rates {
+		k := ratePair{r.From, r.To}
+		rb[k] = append(rb[k], r) // ListRates já ordena por par e data
+	}
+	return rb, nil
+}
+
+// latest devolve a cotação mais recente do par com data <= day
+func (rb rateBook) latest(from, to string, day time.Time) (ExchangeRate, bool) {
+	list := rb[ratePair{from, to}]
+	i, found := slices.BinarySearchFunc(list, day, func(e ExchangeRate, d time.Time) int {
+		return e.Date.Compare(d)
+	})
+	if found {
+		return list[i], true
+	}
+	if i == 0 {
+		return ExchangeRate{}, false
+	}
+	return list[i-1], true
+}
+
+// convert leva amount de "from" para "to" com a cotação vigente em "at" (direta ou inversa,
+// preferindo a mais recente), como fx_convert no Postgres
+func (rb rateBook) convert(amount int64, from, to string, at time.Time) (int64, bool) {
+	if from == to {
+		return amount, true
+	}
+	day := rateDay(at)
+	direct, okD := rb.latest(from, to, day)
+	inverse, okI := rb.latest(to, from, day)
+	switch {
+	case okD && (!okI || !inverse.Date.After(direct.Date)):
+		return convertCents(amount, direct.Rate), true
+	case okI:
+		return convertCents(amount, 1/inverse.Rate), true
+	}
+	return 0, false
+}
+
+// rateDay trunca o instante para o dia (UTC) usado como chave da cotação
+func rateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// convertCents aplica a cotação arredondando para o centavo mais próximo
+func convertCents(amount int64, rate float64) int64 {
+	return int64(math.Round(float64(amount) * rate))
+}
+
+var currencySymbols = map[string]string{
+	"BRL": "R$",
+	"USD": "US$",
+	"EUR": "€",
+	"GBP": "£",
+}
+
+// formatMoney formata centavos com o símbolo da moeda, ex: "R$ 123.45"
+func formatMoney(cents int64, currency string) string {
+	sym, ok := currencySymbols[currency]
+	if !ok {
+		sym = currency
+	}
+	return fmt.Sprintf("%s %.2f", sym, float64(cents)/100.0)
+}
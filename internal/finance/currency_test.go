@@ -0,0 +1,85 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonthlySummary_ConvertsCurrencies(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+
+	d := func(day int) time.Time { return time.Date(2025, 7, day, 15, 0, 0, 0, time.UTC) }
+	err := s.ImportRates(ctx, []ExchangeRate{
+		{From: "usd", To: "BRL", Date: d(1), Rate: 5.0},
+		{From: "USD", To: "BRL", Date: d(10), Rate: 5.5},
+		{From: "BRL", To: "EUR", Date: d(1), Rate: 0.16},
+	})
+	if err != nil {
+		t.Fatalf("import rates: %v", err)
+	}
+
+	for _, in := range []TxInput{
+		{Type: Income, Category: "salary", AmountCents: 100000, OccurredAt: d(5)},
+		{Type: Expense, Category: "hotel", AmountCents: 1000, Currency: "USD", OccurredAt: d(5)},  // 5.0 -> 5000
+		{Type: Expense, Category: "food", AmountCents: 1000, Currency: "USD", OccurredAt: d(12)},  // 5.5 -> 5500
+		{Type: Expense, Category: "museum", AmountCents: 800, Currency: "EUR", OccurredAt: d(20)}, // 1/0.16 -> 5000
+	} {
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatalf("create %+v: %v", in, err)
+		}
+	}
+
+	sum, err := s.MonthlySummary(ctx, 2025, 7, TxFilter{}, "")
+	if err != nil {
+		t.Fatalf("summary: %v", err)
+	}
+	if sum.Currency != "BRL" || sum.Income != 100000 || sum.Expense != 15500 {
+		t.Fatalf("summary mismatch: %+v", sum)
+	}
+
+	if _, err := s.MonthlySummary(ctx, 2025, 7, TxFilter{}, "GBP"); !errors.Is(err, ErrMissingRate) {
+		t.Fatalf("expected ErrMissingRate, got %v", err)
+	}
+
+	report, err := s.GenerateMonthlyReport(ctx, 2025, 7, "")
+	if err != nil || !strings.Contains(report, "R$ 155.00") {
+		t.Fatalf("report: err=%v\n%s", err, report)
+	}
+}
+
+func TestRates_ScopedByLedger(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	bg := context.Background()
+	ana, _ := s.Register(bg, "ana@example.com", "segredo-123")
+	eve, _ := s.Register(bg, "eve@example.com", "segredo-456")
+	actx, ectx := WithUser(bg, ana.ID), WithUser(bg, eve.ID)
+	d := time.Date(2025, 7, 5, 12, 0, 0, 0, time.UTC)
+
+	if err := s.ImportRates(actx, []ExchangeRate{{From: "USD", To: "BRL", Date: d, Rate: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create(actx, TxInput{Type: Expense, Category: "hotel", AmountCents: 1000, Currency: "USD", OccurredAt: d}); err != nil {
+		t.Fatal(err)
+	}
+	// outro usuário grava a própria cotação do mesmo par e dia sem afetar o livro de ana
+	if err := s.ImportRates(ectx, []ExchangeRate{{From: "USD", To: "BRL", Date: d, Rate: 1000}}); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := s.MonthlySummary(actx, 2025, 7, TxFilter{}, "")
+	if err != nil || sum.Expense != 5000 {
+		t.Fatalf("summary: %+v %v", sum, err)
+	}
+	if rs, _ := s.ListRates(ectx, "", ""); len(rs) != 1 || rs[0].Rate != 1000 {
+		t.Errorf("eve rates: %+v", rs)
+	}
+	if _, err := s.Create(ectx, TxInput{Type: Expense, Category: "hotel", AmountCents: 1000, Currency: "EUR", OccurredAt: d}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.MonthlySummary(ectx, 2025, 7, TxFilter{}, ""); !errors.Is(err, ErrMissingRate) {
+		t.Errorf("eve used another ledger's rates: %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package finance
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxForecastMonths      = 24
+	DefaultForecastHistory = 6 // meses de histórico usados nas médias
+	maxForecastHistory     = 36
+)
+
+// ForecastMonth é a projeção de um mês: a média do histórico por categoria, trocada pelos
+// itens conhecidos do mês quando eles são maiores
+type ForecastMonth struct {
+	Month         string `json:"month"` // YYYY-MM
+	Income        int64  `json:"income_cents"`
+	Expense       int64  `json:"expense_cents"`
+	Net           int64  `json:"net_cents"`
+	KnownIncome   int64  `json:"known_income_cents"`  // agendado: recorrências e lançamentos futuros
+	KnownExpense  int64  `json:"known_expense_cents"` // idem, inclusive parcelas
+	EndingBalance int64  `json:"ending_balance_cents"`
+	Low           int64  `json:"low_cents"`  // faixa de um desvio padrão do saldo mensal histórico,
+	High          int64  `json:"high_cents"` // acumulada ao longo dos meses
+}
+
+// CategoryAverage é a média mensal de uma categoria no histórico
+type CategoryAverage struct {
+	Type         TxType `json:"type"`
+	Category     string `json:"category"`
+	AverageCents int64  `json:"average_cents"`
+}
+
+// Forecast projeta o saldo mês a mês a partir do mês seguinte ao atual
+type Forecast struct {
+	Currency       string            `json:"currency"` // moeda base da conversão
+	HistoryMonths  int               `json:"history_months"`
+	BalanceCents   int64             `json:"balance_cents"` // soma dos saldos das contas agora
+	PendingCents   int64             `json:"pending_cents"` // itens conhecidos até o fim do mês atual
+	StartingCents  int64             `json:"starting_balance_cents"`
+	NetStdDevCents int64             `json:"net_stddev_cents"` // desvio padrão do saldo mensal histórico
+	Months         []ForecastMonth   `json:"months"`
+	Averages       []CategoryAverage `json:"averages"`
+}
+
+// Forecast projeta "months" meses (1 a 24) usando a média por categoria dos "history" meses
+// completos anteriores ao atual (0 = DefaultForecastHistory). Os itens já conhecidos
+// (ocorrências de regras recorrentes e transações com data futura, como parcelas) substituem
+// a média da categoria no mês quando são maiores, para não contar duas vezes o que já
+// aparece no histórico (ex: aluguel lançado por uma regra). O saldo inicial é o das contas
+// agora mais os itens conhecidos até o fim do mês atual; transferências não entram.
+func (s *Service) Forecast(ctx context.Context, now time.Time, months, history int, base string) (*Forecast, error) {
+	if history == 0 {
+		history = DefaultForecastHistory
+	}
+	if months < 1 || months > maxForecastMonths {
+		return nil, fmt.Errorf("%w: months must be between 1 and %d", ErrBadRequest, maxForecastMonths)
+	}
+	if history < 1 || history > maxForecastHistory {
+		return nil, fmt.Errorf("%w: history must be between 1 and %d months", ErrBadRequest, maxForecastHistory)
+	}
+	base, err := s.resolveBase(base)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.loadRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convert := func(amount int64, currency string, at time.Time) (int64, error) {
+		v, ok := rates.convert(amount, currency, base, at)
+		if !ok {
+			return 0, fmt.Errorf("%w: %s to %s on %s", ErrMissingRate, currency, base, at.Format("2006-01-02"))
+		}
+		return v, nil
+	}
+
+	now = now.UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	first, end := current.AddDate(0, 1, 0), current.AddDate(0, 1+months, 0)
+	fc := &Forecast{Currency: base, HistoryMonths: history}
+
+	// médias e variação do saldo mensal no histórico
+	past, err := s.ListByPeriod(ctx, current.AddDate(0, -history, 0), current.Add(-time.Microsecond), TxFilter{})
+	if err != nil {
+		return nil, err
+	}
+	sums := map[catKey]int64{}
+	nets := make([]int64, history)
+	for i := range past {
+		t := &past[i]
+		if t.Type == Transfer {
+			continue
+		}
+		for _, line := range t.categoryLines() {
+			amt, err := convert(line.AmountCents, t.Currency, t.OccurredAt)
+			if err != nil {
+				return nil, err
+			}
+			sums[catKey{t.Type, line.Category}] += amt
+			m := (t.OccurredAt.Year()-current.Year())*12 + int(t.OccurredAt.Month()-current.Month()) + history
+			nets[m] += signed(t.Type, amt)
+		}
+	}
+	avg := map[catKey]int64{}
+	for k, v := range sums {
+		avg[k] = int64(math.Round(float64(v) / float64(history)))
+		fc.Averages = append(fc.Averages, CategoryAverage{Type: k.typ, Category: k.category, AverageCents: avg[k]})
+	}
+	slices.SortFunc(fc.Averages, func(a, b CategoryAverage) int {
+		if a.Type != b.Type {
+			return strings.Compare(string(b.Type), string(a.Type)) // income antes de expense
+		}
+		if a.AverageCents != b.AverageCents {
+			return cmp.Compare(b.AverageCents, a.AverageCents)
+		}
+		return strings.Compare(a.Category, b.Category)
+	})
+	if fc.Averages == nil {
+		fc.Averages = []CategoryAverage{}
+	}
+	fc.NetStdDevCents = stdDev(nets)
+
+	// itens conhecidos por mês (índice 0 = resto do mês atual)
+	known := make([]map[catKey]int64, months+1)
+	for i := range known {
+		known[i] = map[catKey]int64{}
+	}
+	addKnown := func(typ TxType, category string, amount int64, currency string, at time.Time) error {
+		if at.Before(now) || !at.Before(end) {
+			return nil
+		}
+		amt, err := convert(amount, currency, at)
+		if err != nil {
+			return err
+		}
+		i := 0
+		if !at.Before(first) {
+			i = (at.Year()-first.Year())*12 + int(at.Month()-first.Month()) + 1
+		}
+		known[i][catKey{typ, category}] += amt
+		return nil
+	}
+	future, err := s.ListByPeriod(ctx, now, end.Add(-time.Microsecond), TxFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for i := range future {
+		t := &future[i]
+		if t.Type == Transfer {
+			continue
+		}
+		for _, line := range t.categoryLines() {
+			if err := addKnown(t.Type, line.Category, line.AmountCents, t.Currency, t.OccurredAt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	occ, err := s.UpcomingOccurrences(ctx, end.Add(-time.Microsecond))
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range occ {
+		if o.Skipped {
+			continue
+		}
+		// ocorrências vencidas ainda não lançadas entram no mês atual
+		at := o.At
+		if at.Before(now) {
+			at = now
+		}
+		if err := addKnown(o.Type, o.Category, o.AmountCents, o.Currency, at); err != nil {
+			return nil, err
+		}
+	}
+
+	accounts, err := s.repo.ListAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range accounts {
+		b, err := s.Balance(ctx, a.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		amt, err := convert(b.BalanceCents, a.Currency, now)
+		if err != nil {
+			return nil, err
+		}
+		fc.BalanceCents += amt
+	}
+	for k, v := range known[0] {
+		fc.PendingCents += signed(k.typ, v)
+	}
+	fc.StartingCents = fc.BalanceCents + fc.PendingCents
+
+	balance := fc.StartingCents
+	fc.Months = make([]ForecastMonth, months)
+	for i := range fc.Months {
+		m := ForecastMonth{Month: first.AddDate(0, i, 0).Format("2006-01")}
+		projected := maps.Clone(avg)
+		for k, v := range known[i+1] {
+			if k.typ == Income {
+				m.KnownIncome += v
+			} else {
+				m.KnownExpense += v
+			}
+			projected[k] = max(projected[k], v)
+		}
+		for k, v := range projected {
+			if k.typ == Income {
+				m.Income += v
+			} else {
+				m.Expense += v
+			}
+		}
+		m.Net = m.Income - m.Expense
+		balance += m.Net
+		m.EndingBalance = balance
+		band := int64(math.Round(float64(fc.NetStdDevCents) * math.Sqrt(float64(i+1))))
+		m.Low, m.High = balance-band, balance+band
+		fc.Months[i] = m
+	}
+	return fc, nil
+}
+
+func signed(typ TxType, amount int64) int64 {
+	if typ == Expense {
+		return -amount
+	}
+	return amount
+}
+
+// stdDev é o desvio padrão (populacional) dos valores, arredondado para o centavo
+func stdDev(v []int64) int64 {
+	if len(v) < 2 {
+		return 0
+	}
+	var sum float64
+	for _, x := range v {
+		sum += float64(x)
+	}
+	mean := sum / float64(len(v))
+	var sq float64
+	for _, x := range v {
+		sq += (float64(x) - mean) * (float64(x) - mean)
+	}
+	return int64(math.Round(math.Sqrt(sq / float64(len(v)))))
+}
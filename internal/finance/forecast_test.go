@@ -0,0 +1,68 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_Forecast(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	now := time.Now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := s.CreateAccount(ctx, AccountInput{Name: "Corrente", Kind: Checking, OpeningBalanceCents: 1000000}); err != nil {
+		t.Fatal(err)
+	}
+	// histórico: saldos mensais de 400000, 360000 e 380000
+	for i, groceries := range []int64{100000, 140000, 120000} {
+		at := current.AddDate(0, i-3, 9).Add(12 * time.Hour)
+		for _, in := range []TxInput{
+			{Type: Income, Category: "Salário", AmountCents: 500000, OccurredAt: at},
+			{Type: Expense, Category: "Mercado", AmountCents: groceries, OccurredAt: at},
+		} {
+			if _, err := s.Create(ctx, in); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// conhecidos: aluguel a partir do mês que vem e duas parcelas futuras
+	if _, err := s.CreateRecurring(ctx, RecurringInput{
+		TxInput:   TxInput{Type: Expense, Category: "Aluguel", AmountCents: 200000},
+		Frequency: Monthly,
+		StartAt:   current.AddDate(0, 1, 0),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateInstallment(ctx, InstallmentInput{
+		TxInput: TxInput{Category: "Casa", AmountCents: 30000, OccurredAt: current},
+		Count:   3,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	fc, err := s.Forecast(ctx, now, 3, 3, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fc.BalanceCents != 1000000 || fc.PendingCents != 0 || fc.NetStdDevCents != 16330 || len(fc.Averages) != 2 {
+		t.Fatalf("unexpected forecast: %+v", fc)
+	}
+	m := fc.Months[0]
+	if m.Month != current.AddDate(0, 1, 0).Format("2006-01") || m.Income != 500000 || m.Expense != 330000 ||
+		m.KnownExpense != 210000 || m.EndingBalance != 1170000 || m.Low != 1153670 || m.High != 1186330 {
+		t.Errorf("first month: %+v", m)
+	}
+	if m := fc.Months[2]; m.Expense != 320000 || m.EndingBalance != 1520000 || m.High-m.EndingBalance != 28284 {
+		t.Errorf("last month: %+v", m)
+	}
+
+	if _, err := s.Forecast(ctx, now, 0, 3, ""); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("zero months: %v", err)
+	}
+	if _, err := s.Forecast(ctx, now, 3, 37, ""); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("history too long: %v", err)
+	}
+}
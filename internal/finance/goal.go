@@ -0,0 +1,191 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// goalTrendMonths é a janela usada para dizer se a meta está no ritmo
+const goalTrendMonths = 3
+
+// Goal é uma meta de economia: juntar TargetCents até TargetDate. O valor guardado vem do
+// saldo da conta vinculada (AccountID) ou, com Tag, do saldo das transações com a tag, que
+// funciona como um envelope: receitas marcadas somam e despesas marcadas subtraem.
+type Goal struct {
+	ID          uuid.UUID     `json:"id"`
+	LedgerID    uuid.UUID     `json:"-"`
+	Name        string        `json:"name"`
+	TargetCents int64         `json:"target_cents"`
+	Currency    string        `json:"currency"` // da conta vinculada ou a moeda base
+	TargetDate  time.Time     `json:"target_date"`
+	AccountID   *uuid.UUID    `json:"account_id,omitempty"`
+	Tag         string        `json:"tag,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	Progress    *GoalProgress `json:"progress,omitempty"` // preenchido pelo Service
+}
+
+// GoalProgress é o andamento da meta calculado na consulta
+type GoalProgress struct {
+	SavedCents           int64   `json:"saved_cents"`
+	RemainingCents       int64   `json:"remaining_cents"`
+	PercentComplete      float64 `json:"percent_complete"`
+	MonthsLeft           int     `json:"months_left"`
+	RequiredMonthlyCents int64   `json:"required_monthly_cents"` // para chegar ao alvo na data
+	RecentMonthlyCents   int64   `json:"recent_monthly_cents"`   // média guardada nos últimos 3 meses
+	Reached              bool    `json:"reached"`
+	OnTrack              bool    `json:"on_track"`
+}
+
+type GoalRepository interface {
+	CreateGoal(ctx context.Context, g *Goal) error
+	GetGoal(ctx context.Context, id uuid.UUID) (*Goal, error)
+	ListGoals(ctx context.Context) ([]Goal, error)
+	DeleteGoal(ctx context.Context, id uuid.UUID) error
+}
+
+// GoalInput descreve a meta; informe AccountID ou Tag, não os dois
+type GoalInput struct {
+	Name        string
+	TargetCents int64
+	TargetDate  time.Time
+	AccountID   *uuid.UUID
+	Tag         string
+}
+
+func (s *Service) CreateGoal(ctx context.Context, in GoalInput) (*Goal, error) {
+	now := time.Now().UTC()
+	g := &Goal{
+		ID:          uuid.New(),
+		LedgerID:    ledgerScope(ctx),
+		Name:        strings.TrimSpace(in.Name),
+		TargetCents: in.TargetCents,
+		TargetDate:  rateDay(in.TargetDate),
+		AccountID:   in.AccountID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	switch {
+	case g.Name == "" || utf8.RuneCountInString(g.Name) > 100:
+		return nil, fmt.Errorf("%w: name must have 1 to 100 characters", ErrBadRequest)
+	case g.TargetCents <= 0:
+		return nil, fmt.Errorf("%w: target_cents must be positive", ErrBadRequest)
+	case in.TargetDate.IsZero() || !g.TargetDate.After(rateDay(now)):
+		return nil, fmt.Errorf("%w: target_date must be in the future", ErrBadRequest)
+	case (in.AccountID == nil) == (strings.TrimSpace(in.Tag) == ""):
+		return nil, fmt.Errorf("%w: goal needs either account_id or tag", ErrBadRequest)
+	}
+	if g.AccountID != nil {
+		a, err := s.repo.GetAccount(ctx, *g.AccountID)
+		if err != nil {
+			return nil, accountRefErr(err)
+		}
+		g.Currency = a.Currency
+	} else {
+		tags, err := normalizeTags([]string{in.Tag})
+		if err != nil {
+			return nil, err
+		}
+		g.Tag, g.Currency = tags[0], s.baseCurrency
+	}
+	if err := s.repo.CreateGoal(ctx, g); err != nil {
+		return nil, err
+	}
+	if err := s.fillGoalProgress(ctx, g, now); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (s *Service) GetGoal(ctx context.Context, id uuid.UUID) (*Goal, error) {
+	g, err := s.repo.GetGoal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillGoalProgress(ctx, g, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ListGoals lista as metas do livro com o andamento, das mais próximas para as mais distantes
+func (s *Service) ListGoals(ctx context.Context) ([]Goal, error) {
+	goals, err := s.repo.ListGoals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := range goals {
+		if err := s.fillGoalProgress(ctx, &goals[i], now); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortFunc(goals, func(a, b Goal) int {
+		if c := a.TargetDate.Compare(b.TargetDate); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return goals, nil
+}
+
+func (s *Service) DeleteGoal(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteGoal(ctx, id)
+}
+
+// goalSaved devolve o valor guardado até "at", na moeda da meta
+func (s *Service) goalSaved(ctx context.Context, g *Goal, at time.Time) (int64, error) {
+	if g.AccountID != nil {
+		b, err := s.Balance(ctx, *g.AccountID, at)
+		if err != nil {
+			return 0, err
+		}
+		return b.BalanceCents, nil
+	}
+	sum, err := s.TagSummary(ctx, time.Time{}, at, TxFilter{Tags: []string{g.Tag}}, g.Currency)
+	if err != nil {
+		return 0, err
+	}
+	for _, tt := range sum.Tags {
+		if tt.Tag == g.Tag {
+			return tt.NetCents, nil
+		}
+	}
+	return 0, nil
+}
+
+// fillGoalProgress calcula o andamento em "now". O aporte mensal necessário divide o que
+// falta pelos meses até a data alvo (ao menos um); a meta está no ritmo se a média guardada
+// nos últimos três meses cobre esse aporte.
+func (s *Service) fillGoalProgress(ctx context.Context, g *Goal, now time.Time) error {
+	saved, err := s.goalSaved(ctx, g, now)
+	if err != nil {
+		return err
+	}
+	before, err := s.goalSaved(ctx, g, now.AddDate(0, -goalTrendMonths, 0))
+	if err != nil {
+		return err
+	}
+	p := &GoalProgress{
+		SavedCents:         saved,
+		RemainingCents:     max(g.TargetCents-saved, 0),
+		PercentComplete:    math.Round(float64(saved)*10000/float64(g.TargetCents)) / 100,
+		RecentMonthlyCents: int64(math.Round(float64(saved-before) / goalTrendMonths)),
+		Reached:            saved >= g.TargetCents,
+	}
+	if g.TargetDate.After(now) {
+		p.MonthsLeft = max((g.TargetDate.Year()-now.Year())*12+int(g.TargetDate.Month()-now.Month()), 1)
+	}
+	months := int64(max(p.MonthsLeft, 1)) // prazo vencido: todo o restante é necessário agora
+	p.RequiredMonthlyCents = (p.RemainingCents + months - 1) / months
+	p.OnTrack = p.Reached || (p.MonthsLeft > 0 && p.RecentMonthlyCents >= p.RequiredMonthlyCents)
+	g.Progress = p
+	return nil
+}
@@ -0,0 +1,81 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_Goals(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	acc, err := s.CreateAccount(ctx, AccountInput{Name: "Reserva", Kind: Savings})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 200000 antes da janela de três meses e 100000 por mês dentro dela
+	for _, d := range []struct {
+		months int
+		amount int64
+	}{{-5, 200000}, {-2, 100000}, {-1, 100000}, {0, 100000}} {
+		at := now.AddDate(0, d.months, 0).Add(-time.Hour)
+		in := TxInput{Type: Income, Category: "Aporte", AmountCents: d.amount, OccurredAt: at, AccountID: &acc.ID}
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	target := time.Date(now.Year(), now.Month()+7, 1, 0, 0, 0, 0, time.UTC)
+	g, err := s.CreateGoal(ctx, GoalInput{Name: "Reserva de emergência", TargetCents: 1200000, TargetDate: target, AccountID: &acc.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := g.Progress
+	if p.SavedCents != 500000 || p.RemainingCents != 700000 || p.PercentComplete != 41.67 || p.MonthsLeft != 7 ||
+		p.RequiredMonthlyCents != 100000 || p.RecentMonthlyCents != 100000 || !p.OnTrack || p.Reached {
+		t.Errorf("account goal: %+v", p)
+	}
+
+	for _, in := range []TxInput{
+		{Type: Income, Category: "Bônus", AmountCents: 300000, OccurredAt: now.AddDate(0, -1, 0), Tags: []string{"carro"}},
+		{Type: Expense, Category: "Oficina", AmountCents: 50000, OccurredAt: now.AddDate(0, 0, -10), Tags: []string{"carro"}},
+	} {
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	car, err := s.CreateGoal(ctx, GoalInput{Name: "Carro", TargetCents: 250000, TargetDate: target.AddDate(1, 0, 0), Tag: " Carro "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if car.Tag != "carro" || car.Progress.SavedCents != 250000 || !car.Progress.Reached || car.Progress.RequiredMonthlyCents != 0 {
+		t.Errorf("tag goal: %+v %+v", car, car.Progress)
+	}
+
+	if _, err := s.CreateGoal(ctx, GoalInput{Name: "x", TargetCents: 1, TargetDate: target, AccountID: &acc.ID, Tag: "carro"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("account and tag: %v", err)
+	}
+	if _, err := s.CreateGoal(ctx, GoalInput{Name: "x", TargetCents: 1, TargetDate: now.AddDate(0, 0, -1), Tag: "carro"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("past target date: %v", err)
+	}
+
+	goals, err := s.ListGoals(ctx)
+	if err != nil || len(goals) != 2 || goals[0].ID != g.ID || goals[1].Progress == nil {
+		t.Errorf("list: %+v %v", goals, err)
+	}
+	empty, _ := s.CreateAccount(ctx, AccountInput{Name: "Viagem", Kind: Savings})
+	if _, err := s.CreateGoal(ctx, GoalInput{Name: "Viagem", TargetCents: 1, TargetDate: target, AccountID: &empty.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteAccount(ctx, empty.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("delete account with goal: %v", err)
+	}
+	if err := s.DeleteGoal(ctx, car.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetGoal(ctx, car.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted goal: %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package finance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIdempotencyMismatch indica uma Idempotency-Key reutilizada com outro corpo
+var ErrIdempotencyMismatch = errors.New("idempotency key reused with a different request")
+
+// DefaultIdempotencyTTL é por quanto tempo uma Idempotency-Key é lembrada
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// MaxIdempotencyKeyLen limita o tamanho da chave enviada pelo cliente
+const MaxIdempotencyKeyLen = 255
+
+// IdempotencyRecord guarda a resposta de uma criação para ser repetida em retentativas.
+// Só criações bem-sucedidas são registradas, então a resposta é sempre um 201.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	Response    []byte // JSON da transação criada
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+type IdempotencyRepository interface {
+	// GetIdempotencyKey devolve ErrNotFound se a chave não existe ou expirou antes de "now"
+	GetIdempotencyKey(ctx context.Context, key string, now time.Time) (*IdempotencyRecord, error)
+	// CreateIdempotent grava a transação e a chave atomicamente; ErrConflict se a chave
+	// já estiver em uso (não expirada)
+	CreateIdempotent(ctx context.Context, t *Transaction, rec *IdempotencyRecord) error
+	// PurgeIdempotencyKeys remove as chaves expiradas antes de "now"
+	PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+// WithIdempotencyTTL define a validade das Idempotency-Keys
+func WithIdempotencyTTL(d time.Duration) Option {
+	return func(s *Service) {
+		if d > 0 {
+			s.idempotencyTTL = d
+		}
+	}
+}
+
+// CreateIdempotent cria a transação uma única vez por chave. Retentativas com o mesmo
+// requestHash devolvem o registro original (replayed=true); com outro hash, falham com
+// ErrIdempotencyMismatch.
+func (s *Service) CreateIdempotent(ctx context.Context, key, requestHash string, in TxInput) (rec *IdempotencyRecord, replayed bool, err error) {
+	if key == "" || len(key) > MaxIdempotencyKeyLen {
+		return nil, false, fmt.Errorf("%w: Idempotency-Key must have 1 to %d characters", ErrBadRequest, MaxIdempotencyKeyLen)
+	}
+	now := time.Now().UTC()
+	if rec, err := s.replayIdempotent(ctx, key, requestHash, now); !errors.Is(err, ErrNotFound) {
+		return rec, err == nil, err
+	}
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	tx, err := s.newTx(ctx, in, now, cats)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.saveCategories(ctx, cats, tx); err != nil {
+		return nil, false, err
+	}
+	body, err := json.Marshal(tx)
+	if err != nil {
+		return nil, false, err
+	}
+	rec = &IdempotencyRecord{
+		Key:         key,
+		RequestHash: requestHash,
+		Response:    body,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.idempotencyTTL),
+	}
+	err = s.repo.CreateIdempotent(ctx, tx, rec)
+	if errors.Is(err, ErrConflict) {
+		// outra requisição com a mesma chave venceu a corrida
+		rec, err = s.replayIdempotent(ctx, key, requestHash, now)
+		return rec, err == nil, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return rec, false, nil
+}
+
+func (s *Service) replayIdempotent(ctx context.Context, key, requestHash string, now time.Time) (*IdempotencyRecord, error) {
+	rec, err := s.repo.GetIdempotencyKey(ctx, key, now)
+	if err != nil {
+		return nil, err
+	}
+	if rec.RequestHash != requestHash {
+		return nil, ErrIdempotencyMismatch
+	}
+	return rec, nil
+}
+
+// PurgeIdempotencyKeys remove as chaves expiradas
+func (s *Service) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	return s.repo.PurgeIdempotencyKeys(ctx, time.Now().UTC())
+}
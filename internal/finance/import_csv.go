@@ -0,0 +1,276 @@
+package finance
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Convenções de sinal do valor no extrato
+const (
+	SignNegativeIsExpense = "negative_is_expense" // extrato de conta: débitos negativos
+	SignPositiveIsExpense = "positive_is_expense" // fatura de cartão: compras positivas
+)
+
+// CSVMapping descreve como ler um extrato CSV. Colunas são referenciadas pelo nome do
+// cabeçalho (HasHeader) ou pelo índice a partir de 0.
+type CSVMapping struct {
+	Delimiter         string     `json:"delimiter"` // padrão ","
+	HasHeader         bool       `json:"has_header"`
+	DateColumn        string     `json:"date_column"`
+	DateFormat        string     `json:"date_format"` // ex: DD/MM/YYYY (padrão YYYY-MM-DD) ou layout Go
+	AmountColumn      string     `json:"amount_column"`
+	DecimalSeparator  string     `json:"decimal_separator"` // "," ou "." (padrão ".")
+	SignConvention    string     `json:"sign_convention"`   // negative_is_expense | positive_is_expense
+	CategoryColumn    string     `json:"category_column"`   // opcional
+	DefaultCategory   string     `json:"default_category"`  // usada quando a coluna está vazia
+	DescriptionColumn string     `json:"description_column"`
+	Currency          string     `json:"currency"`   // opcional
+	AccountID         *uuid.UUID `json:"account_id"` // opcional
+}
+
+// ImportRow é o resultado de uma linha do arquivo (Line começa em 1, contando o cabeçalho)
+type ImportRow struct {
+	Line        int          `json:"line"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Skipped     bool         `json:"skipped,omitempty"` // já importada anteriormente
+}
+
+type ImportResult struct {
+	DryRun   bool        `json:"dry_run"`
+	Imported int         `json:"imported"`
+	Failed   int         `json:"failed"`
+	Skipped  int         `json:"skipped"`
+	Rows     []ImportRow `json:"rows"`
+}
+
+// Importer converte extratos bancários em transações, validando com as regras do Service
+type Importer struct {
+	svc *Service
+}
+
+func NewImporter(svc *Service) *Importer { return &Importer{svc: svc} }
+
+// csvColumns guarda os índices resolvidos das colunas do mapeamento (-1 = ausente)
+type csvColumns struct {
+	date, amount, category, description int
+}
+
+func resolveColumn(ref string, header map[string]int, required bool) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		if required {
+			return -1, fmt.Errorf("%w: missing column mapping", ErrBadRequest)
+		}
+		return -1, nil
+	}
+	if i, ok := header[strings.ToLower(ref)]; ok {
+		return i, nil
+	}
+	if i, err := strconv.Atoi(ref); err == nil && i >= 0 {
+		return i, nil
+	}
+	return -1, fmt.Errorf("%w: unknown column %q", ErrBadRequest, ref)
+}
+
+// dateLayout aceita tokens amigáveis (YYYY, MM, DD, HH, mm, ss) ou um layout Go
+func dateLayout(f string) string {
+	if f == "" {
+		return "2006-01-02"
+	}
+	return strings.NewReplacer("YYYY", "2006", "MM", "01", "DD", "02", "HH", "15", "mm", "04", "ss", "05").Replace(f)
+}
+
+// parseAmountCents converte "1.234,56", "-12.30", "(45,00)" ou "R$ 10,00" em centavos
+// sem passar por ponto flutuante. O sinal é preservado.
+func parseAmountCents(s, decimalSep string) (int64, error) {
+	thousands := ","
+	if decimalSep == "," {
+		thousands = "."
+	} else {
+		decimalSep = "."
+	}
+	s = strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		neg, s = true, s[1:len(s)-1]
+	}
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '-', r == '+':
+			return r
+		case string(r) == decimalSep:
+			return '.'
+		}
+		return -1 // descarta símbolo de moeda, espaços e separador de milhar
+	}, strings.ReplaceAll(s, thousands, ""))
+	if strings.HasPrefix(s, "-") {
+		neg, s = !neg, s[1:]
+	} else if strings.HasSuffix(s, "-") {
+		neg, s = !neg, s[:len(s)-1]
+	}
+	s = strings.TrimPrefix(s, "+")
+
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" && frac == "" {
+		return 0, errors.New("empty amount")
+	}
+	if len(frac) > 2 {
+		return 0, fmt.Errorf("amount %q has more than 2 decimal places", s)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	if intPart == "" {
+		intPart = "0"
+	}
+	cents, err := strconv.ParseInt(intPart+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if neg {
+		cents = -cents
+	}
+	return cents, nil
+}
+
+// ImportCSV lê o arquivo conforme o mapeamento. Em dryRun nada é gravado e as linhas
+// interpretadas são devolvidas; caso contrário as linhas válidas são gravadas uma a uma
+// via Repository.Create e as inválidas são reportadas.
+func (im *Importer) ImportCSV(ctx context.Context, r io.Reader, m CSVMapping, dryRun bool) (*ImportResult, error) {
+	switch m.SignConvention {
+	case "":
+		m.SignConvention = SignNegativeIsExpense
+	case SignNegativeIsExpense, SignPositiveIsExpense:
+	default:
+		return nil, fmt.Errorf("%w: invalid sign_convention", ErrBadRequest)
+	}
+	if m.DecimalSeparator != "" && m.DecimalSeparator != "," && m.DecimalSeparator != "." {
+		return nil, fmt.Errorf("%w: decimal_separator must be ',' or '.'", ErrBadRequest)
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	if m.Delimiter != "" {
+		d := []rune(m.Delimiter)
+		if len(d) != 1 {
+			return nil, fmt.Errorf("%w: delimiter must be a single character", ErrBadRequest)
+		}
+		cr.Comma = d[0]
+	}
+
+	header := map[string]int{}
+	line := 0
+	if m.HasHeader {
+		rec, err := cr.Read()
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading header: %v", ErrBadRequest, err)
+		}
+		line++
+		for i, h := range rec {
+			header[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+		}
+	}
+	var cols csvColumns
+	var err error
+	if cols.date, err = resolveColumn(m.DateColumn, header, true); err != nil {
+		return nil, err
+	}
+	if cols.amount, err = resolveColumn(m.AmountColumn, header, true); err != nil {
+		return nil, err
+	}
+	if cols.category, err = resolveColumn(m.CategoryColumn, header, false); err != nil {
+		return nil, err
+	}
+	if cols.description, err = resolveColumn(m.DescriptionColumn, header, false); err != nil {
+		return nil, err
+	}
+	layout := dateLayout(m.DateFormat)
+
+	cats, err := im.svc.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &ImportResult{DryRun: dryRun, Rows: []ImportRow{}}
+	now := time.Now().UTC()
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrBadRequest, line, err)
+		}
+		if len(res.Rows) >= MaxBatchSize {
+			return nil, fmt.Errorf("%w: file exceeds %d rows", ErrBadRequest, MaxBatchSize)
+		}
+		row := ImportRow{Line: line}
+		tx, err := im.csvRow(ctx, rec, cols, m, layout, now, cats)
+		if err == nil && !dryRun {
+			if err = im.svc.saveCategories(ctx, cats, tx); err == nil {
+				err = im.svc.repo.Create(ctx, tx)
+			}
+		}
+		if err != nil {
+			row.Error = err.Error()
+			res.Failed++
+		} else {
+			row.Transaction = tx
+			res.Imported++
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	if dryRun {
+		res.Imported = 0
+	}
+	return res, nil
+}
+
+func field(rec []string, i int) string {
+	if i < 0 || i >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[i])
+}
+
+func (im *Importer) csvRow(ctx context.Context, rec []string, cols csvColumns, m CSVMapping, layout string, now time.Time, cats *categorySet) (*Transaction, error) {
+	if cols.date >= len(rec) || cols.amount >= len(rec) {
+		return nil, fmt.Errorf("%w: row has only %d columns", ErrBadRequest, len(rec))
+	}
+	at, err := time.Parse(layout, field(rec, cols.date))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date %q", ErrBadRequest, field(rec, cols.date))
+	}
+	cents, err := parseAmountCents(field(rec, cols.amount), m.DecimalSeparator)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadRequest, err)
+	}
+	typ := Income
+	if (cents < 0) == (m.SignConvention == SignNegativeIsExpense) {
+		typ = Expense
+	}
+	if cents < 0 {
+		cents = -cents
+	}
+	category := field(rec, cols.category)
+	if category == "" {
+		category = m.DefaultCategory
+	}
+	return im.svc.newTx(ctx, TxInput{
+		Type:        typ,
+		Category:    category,
+		AmountCents: cents,
+		Currency:    m.Currency,
+		Description: field(rec, cols.description),
+		OccurredAt:  at,
+		AccountID:   m.AccountID,
+	}, now, cats)
+}
@@ -0,0 +1,80 @@
+package finance
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseAmountCents(t *testing.T) {
+	cases := []struct {
+		in   string
+		sep  string
+		want int64
+	}{
+		{"1.234,56", ",", 123456},
+		{"-12,3", ",", -1230},
+		{"(45,00)", ",", -4500},
+		{"R$ 10,00", ",", 1000},
+		{"1,234.56", ".", 123456},
+		{"-0.99", "", -99},
+		{"150", ".", 15000},
+	}
+	for _, c := range cases {
+		got, err := parseAmountCents(c.in, c.sep)
+		if err != nil || got != c.want {
+			t.Errorf("parseAmountCents(%q, %q) = %d, %v; want %d", c.in, c.sep, got, err, c.want)
+		}
+	}
+	if _, err := parseAmountCents("1,234", "."); err != nil {
+		t.Errorf("thousands only: %v", err)
+	}
+	if _, err := parseAmountCents("1.005", "."); err == nil {
+		t.Error("expected error for 3 decimal places")
+	}
+}
+
+func TestImporter_CSVDryRunAndCommit(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	im := NewImporter(s)
+	ctx := context.Background()
+
+	const file = "Data;Histórico;Valor;Categoria\n" +
+		"05/06/2025;Salário ACME;5.000,00;salary\n" +
+		"07/06/2025;Padaria;-23,50;\n" +
+		"xx/06/2025;Quebrada;-1,00;food\n"
+	m := CSVMapping{
+		Delimiter:         ";",
+		HasHeader:         true,
+		DateColumn:        "data",
+		DateFormat:        "DD/MM/YYYY",
+		AmountColumn:      "Valor",
+		DecimalSeparator:  ",",
+		CategoryColumn:    "Categoria",
+		DefaultCategory:   "uncategorized",
+		DescriptionColumn: "1",
+	}
+
+	res, err := im.ImportCSV(ctx, strings.NewReader(file), m, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(res.Rows) != 3 || res.Failed != 1 || res.Imported != 0 || res.Rows[2].Error == "" {
+		t.Fatalf("dry run result: %+v", res)
+	}
+	if tx := res.Rows[1].Transaction; tx.Type != Expense || tx.AmountCents != 2350 || tx.Category != "uncategorized" || tx.Description != "Padaria" {
+		t.Fatalf("parsed row: %+v", tx)
+	}
+	if sum, _ := s.MonthlySummary(ctx, 2025, 6, TxFilter{}, ""); sum.CountTx != 0 {
+		t.Fatalf("dry run persisted rows: %+v", sum)
+	}
+
+	res, err = im.ImportCSV(ctx, strings.NewReader(file), m, false)
+	if err != nil || res.Imported != 2 {
+		t.Fatalf("commit: err=%v res=%+v", err, res)
+	}
+	sum, _ := s.MonthlySummary(ctx, 2025, 6, TxFilter{}, "")
+	if sum.Income != 500000 || sum.Expense != 2350 {
+		t.Fatalf("summary after import: %+v", sum)
+	}
+}
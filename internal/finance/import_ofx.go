@@ -0,0 +1,287 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// OFXEntry é um lançamento (STMTTRN) lido do extrato
+type OFXEntry struct {
+	TrnType  string
+	Posted   time.Time
+	Cents    int64 // com sinal, como no arquivo
+	FITID    string
+	Name     string
+	Memo     string
+	Currency string // CURDEF do extrato
+}
+
+// ParseOFX lê extratos OFX 1.x (SGML, tags sem fechamento) e 2.x (XML). O parser é
+// tolerante: procura os blocos <STMTTRN> e lê cada <TAG>valor até o próximo "<".
+func ParseOFX(r io.Reader) ([]OFXEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	text := decodeOFXText(data)
+	// só ASCII: os índices achados em upper precisam valer também em text
+	upper := asciiUpper(text)
+	if !strings.Contains(upper, "<OFX>") {
+		return nil, fmt.Errorf("%w: not an OFX file", ErrBadRequest)
+	}
+
+	currency := ""
+	if i := strings.Index(upper, "<CURDEF>"); i >= 0 {
+		currency = strings.TrimSpace(ofxValue(text[i+len("<CURDEF>"):]))
+	}
+
+	var out []OFXEntry
+	for {
+		start := strings.Index(upper, "<STMTTRN>")
+		if start < 0 {
+			break
+		}
+		body := text[start+len("<STMTTRN>"):]
+		bodyUpper := upper[start+len("<STMTTRN>"):]
+		end := strings.Index(bodyUpper, "</STMTTRN>")
+		if end < 0 {
+			// SGML pode omitir o fechamento; o bloco termina no próximo STMTTRN ou no fim da lista
+			end = len(bodyUpper)
+			for _, stop := range []string{"<STMTTRN>", "</BANKTRANLIST>"} {
+				if i := strings.Index(bodyUpper, stop); i >= 0 && i < end {
+					end = i
+				}
+			}
+		}
+		fields := ofxFields(body[:end])
+		e, err := ofxEntry(fields, currency)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+		text, upper = body[end:], bodyUpper[end:]
+	}
+	return out, nil
+}
+
+// decodeOFXText converte arquivos em Latin-1/CP1252 (comuns em bancos brasileiros) para UTF-8
+func decodeOFXText(data []byte) string {
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	var b strings.Builder
+	b.Grow(len(data))
+	for _, c := range data {
+		b.WriteRune(rune(c))
+	}
+	return b.String()
+}
+
+// asciiUpper troca apenas a-z por A-Z, sem mudar o tamanho em bytes (strings.ToUpper muda
+// o de alguns caracteres, ex: "ȿ" e "ı")
+func asciiUpper(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'a' <= c && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return string(b)
+}
+
+// ofxValue devolve o texto até o próximo "<" (ou fim de linha), como no SGML
+func ofxValue(s string) string {
+	if i := strings.IndexAny(s, "<\r\n"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// ofxFields extrai os pares TAG=valor de um bloco, ignorando tags de fechamento
+func ofxFields(block string) map[string]string {
+	fields := map[string]string{}
+	for {
+		i := strings.IndexByte(block, '<')
+		if i < 0 {
+			return fields
+		}
+		block = block[i+1:]
+		j := strings.IndexByte(block, '>')
+		if j < 0 {
+			return fields
+		}
+		tag := strings.ToUpper(strings.TrimSpace(block[:j]))
+		block = block[j+1:]
+		if strings.HasPrefix(tag, "/") {
+			continue
+		}
+		if _, seen := fields[tag]; !seen {
+			fields[tag] = ofxUnescape(ofxValue(block))
+		}
+	}
+}
+
+var ofxEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&", "&apos;", "'", "&quot;", `"`)
+
+func ofxUnescape(s string) string { return ofxEntities.Replace(s) }
+
+func ofxEntry(f map[string]string, currency string) (OFXEntry, error) {
+	e := OFXEntry{
+		TrnType:  strings.ToUpper(f["TRNTYPE"]),
+		FITID:    f["FITID"],
+		Name:     f["NAME"],
+		Memo:     f["MEMO"],
+		Currency: currency,
+	}
+	if e.FITID == "" {
+		return e, fmt.Errorf("%w: STMTTRN without FITID", ErrBadRequest)
+	}
+	posted, err := parseOFXDate(f["DTPOSTED"])
+	if err != nil {
+		return e, fmt.Errorf("%w: FITID %s: %v", ErrBadRequest, e.FITID, err)
+	}
+	e.Posted = posted
+	sep := "."
+	if amt := f["TRNAMT"]; strings.Contains(amt, ",") && !strings.Contains(amt, ".") {
+		sep = ","
+	}
+	if e.Cents, err = parseAmountCents(f["TRNAMT"], sep); err != nil {
+		return e, fmt.Errorf("%w: FITID %s: %v", ErrBadRequest, e.FITID, err)
+	}
+	return e, nil
+}
+
+// parseOFXDate aceita YYYYMMDD[HHMMSS[.XXX]][[+-]H[:TZ]], ex: 20250105120000[-3:BRT]
+func parseOFXDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := time.UTC
+	if i := strings.IndexByte(s, '['); i >= 0 {
+		tz := strings.TrimSuffix(s[i+1:], "]")
+		s = s[:i]
+		off, _, _ := strings.Cut(tz, ":")
+		h, err := strconv.ParseFloat(off, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid DTPOSTED timezone %q", tz)
+		}
+		loc = time.FixedZone("", int(h*3600))
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		s = s[:i]
+	}
+	var layout string
+	switch len(s) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid DTPOSTED %q", s)
+	}
+	return time.ParseInLocation(layout, s, loc)
+}
+
+// Tipos OFX cujo sentido é inequívoco; os demais (OTHER, XFER...) seguem o sinal de TRNAMT
+var (
+	ofxCreditTypes = map[string]bool{"CREDIT": true, "DEP": true, "INT": true, "DIV": true, "DIRECTDEP": true}
+	ofxDebitTypes  = map[string]bool{"DEBIT": true, "FEE": true, "SRVCHG": true, "ATM": true, "POS": true,
+		"PAYMENT": true, "CHECK": true, "CASH": true, "DIRECTDEBIT": true, "REPEATPMT": true}
+)
+
+// ofxCategories sugere a categoria pelo TRNTYPE; os demais usam OFXOptions.DefaultCategory
+var ofxCategories = map[string]string{
+	"INT": "interest", "DIV": "dividends", "FEE": "fees", "SRVCHG": "fees", "ATM": "cash",
+}
+
+func ofxTxType(e OFXEntry) TxType {
+	switch {
+	case ofxCreditTypes[e.TrnType]:
+		return Income
+	case ofxDebitTypes[e.TrnType]:
+		return Expense
+	case e.Cents < 0:
+		return Expense
+	}
+	return Income
+}
+
+// OFXOptions complementa o extrato com dados que o arquivo não traz
+type OFXOptions struct {
+	AccountID       *uuid.UUID
+	DefaultCategory string // padrão "uncategorized"
+}
+
+// ImportOFX grava os lançamentos do extrato. O FITID é guardado em ExternalID e, junto com
+// a conta, impede duplicatas: reimportar o mesmo arquivo só gera entradas "skipped".
+func (im *Importer) ImportOFX(ctx context.Context, r io.Reader, opt OFXOptions) (*ImportResult, error) {
+	entries, err := ParseOFX(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: file exceeds %d entries", ErrBadRequest, MaxBatchSize)
+	}
+	if opt.DefaultCategory == "" {
+		opt.DefaultCategory = "uncategorized"
+	}
+	cats, err := im.svc.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &ImportResult{Rows: []ImportRow{}}
+	now := time.Now().UTC()
+	for i, e := range entries {
+		row := ImportRow{Line: i + 1}
+		category := ofxCategories[e.TrnType]
+		if category == "" {
+			category = opt.DefaultCategory
+		}
+		desc := e.Name
+		if e.Memo != "" && !strings.EqualFold(e.Memo, e.Name) {
+			desc = strings.TrimSpace(desc + " " + e.Memo)
+		}
+		cents := e.Cents
+		if cents < 0 {
+			cents = -cents
+		}
+		tx, err := im.svc.newTx(ctx, TxInput{
+			Type:        ofxTxType(e),
+			Category:    category,
+			AmountCents: cents,
+			Currency:    e.Currency,
+			Description: desc,
+			OccurredAt:  e.Posted,
+			AccountID:   opt.AccountID,
+		}, now, cats)
+		if err == nil {
+			err = im.svc.saveCategories(ctx, cats, tx)
+		}
+		if err == nil {
+			tx.ExternalID = e.FITID
+			var inserted bool
+			if inserted, err = im.svc.repo.CreateExternal(ctx, tx); err == nil && !inserted {
+				row.Skipped = true
+			}
+		}
+		switch {
+		case err != nil:
+			row.Error = err.Error()
+			res.Failed++
+		case row.Skipped:
+			res.Skipped++
+		default:
+			row.Transaction = tx
+			res.Imported++
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	return res, nil
+}
@@ -0,0 +1,127 @@
+package finance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+CHARSET:1252
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>BRL
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250603120000[-3:BRT]
+<TRNAMT>-23.50
+<FITID>202506030001
+<MEMO>Padaria
+<STMTTRN>
+<TRNTYPE>OTHER
+<DTPOSTED>20250605
+<TRNAMT>5000,00
+<FITID>202506050001
+<NAME>Salario ACME
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+const ofxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="211"?>
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>BRL</CURDEF>
+<BANKTRANLIST>
+<STMTTRN><TRNTYPE>FEE</TRNTYPE><DTPOSTED>20250610</DTPOSTED><TRNAMT>-12.90</TRNAMT><FITID>X1</FITID><NAME>Tarifa &amp; IOF</NAME></STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+func TestParseOFX(t *testing.T) {
+	es, err := ParseOFX(strings.NewReader(ofxSGML))
+	if err != nil {
+		t.Fatalf("sgml: %v", err)
+	}
+	if len(es) != 2 {
+		t.Fatalf("want 2 entries, got %d", len(es))
+	}
+	if es[0].Cents != -2350 || es[0].FITID != "202506030001" || es[0].Currency != "BRL" {
+		t.Errorf("unexpected first entry: %+v", es[0])
+	}
+	if want := time.Date(2025, 6, 3, 15, 0, 0, 0, time.UTC); !es[0].Posted.Equal(want) {
+		t.Errorf("posted = %v, want %v", es[0].Posted, want)
+	}
+	if es[1].Cents != 500000 || ofxTxType(es[1]) != Income {
+		t.Errorf("unexpected second entry: %+v", es[1])
+	}
+
+	es, err = ParseOFX(strings.NewReader(ofxXML))
+	if err != nil || len(es) != 1 {
+		t.Fatalf("xml: %v, %d entries", err, len(es))
+	}
+	if es[0].Name != "Tarifa & IOF" || ofxTxType(es[0]) != Expense {
+		t.Errorf("unexpected xml entry: %+v", es[0])
+	}
+
+	if _, err := ParseOFX(strings.NewReader("data;valor\n")); err == nil {
+		t.Error("expected error for non-OFX input")
+	}
+}
+
+func TestParseOFX_MultiByteText(t *testing.T) {
+	// ToUpper aumenta "ȿ" (2 → 3 bytes) e diminui "ı" (2 → 1 byte)
+	name, memo := strings.Repeat("ȿ", 200), "Café ıstanbul"
+	doc := "<OFX><BANKTRANLIST>" +
+		"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20250603<TRNAMT>-1.00<FITID>A1<NAME>" + name + "<MEMO>" + memo + "</STMTTRN>" +
+		"<stmttrn><TRNTYPE>CREDIT<DTPOSTED>20250604<TRNAMT>2.00<FITID>A2<NAME>" + memo + "</stmttrn>" +
+		"</BANKTRANLIST></OFX>"
+	es, err := ParseOFX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(es) != 2 || es[0].Name != name || es[0].Memo != memo || es[1].FITID != "A2" || es[1].Name != memo {
+		t.Errorf("unexpected entries: %+v", es)
+	}
+}
+
+func TestImporter_OFXSkipsDuplicates(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	im := NewImporter(s)
+	ctx := context.Background()
+	acc, err := s.CreateAccount(ctx, AccountInput{Name: "Conta", Kind: Checking})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt := OFXOptions{AccountID: &acc.ID}
+
+	res, err := im.ImportOFX(ctx, strings.NewReader(ofxSGML), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 2 || res.Skipped != 0 || res.Failed != 0 {
+		t.Fatalf("first import: %+v", res)
+	}
+	if tx := res.Rows[0].Transaction; tx.Type != Expense || tx.AmountCents != 2350 || tx.ExternalID != "202506030001" {
+		t.Errorf("unexpected transaction: %+v", tx)
+	}
+
+	res, err = im.ImportOFX(ctx, strings.NewReader(ofxSGML), opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Imported != 0 || res.Skipped != 2 {
+		t.Fatalf("re-import: %+v", res)
+	}
+	bal, err := s.Balance(ctx, acc.ID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bal.BalanceCents != 500000-2350 {
+		t.Errorf("balance = %d", bal.BalanceCents)
+	}
+}
@@ -0,0 +1,250 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxInstallments limita o número de parcelas de uma compra
+const maxInstallments = 120
+
+type InstallmentStatus string
+
+const (
+	InstallmentActive    InstallmentStatus = "active"
+	InstallmentCancelled InstallmentStatus = "cancelled" // parcelas restantes removidas
+	InstallmentPrepaid   InstallmentStatus = "prepaid"   // parcelas restantes quitadas de uma vez
+)
+
+// InstallmentRemainder indica qual parcela recebe os centavos que sobram da divisão
+type InstallmentRemainder string
+
+const (
+	RemainderFirst InstallmentRemainder = "first"
+	RemainderLast  InstallmentRemainder = "last"
+)
+
+// Installment é uma compra parcelada ("em 10x"): o total é dividido em Count despesas
+// vinculadas (InstallmentID), uma por mês a partir de FirstAt, para que cada mês do resumo
+// receba só a sua parcela.
+type Installment struct {
+	ID          uuid.UUID            `json:"id"`
+	LedgerID    uuid.UUID            `json:"-"`
+	Description string               `json:"description,omitempty"`
+	TotalCents  int64                `json:"total_cents"`
+	Currency    string               `json:"currency"`
+	Count       int                  `json:"installments"`
+	Remainder   InstallmentRemainder `json:"remainder"`
+	FirstAt     time.Time            `json:"first_at"`
+	Status      InstallmentStatus    `json:"status"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+
+	Transactions []Transaction `json:"transactions,omitempty"` // parcelas, preenchidas pelo Service
+}
+
+type InstallmentRepository interface {
+	// CreateInstallment grava a compra e as parcelas na mesma transação
+	CreateInstallment(ctx context.Context, p *Installment, txs []*Transaction) error
+	GetInstallment(ctx context.Context, id uuid.UUID) (*Installment, error)
+	ListInstallments(ctx context.Context) ([]Installment, error)
+	// InstallmentTxs lista as parcelas da compra pela ordem das parcelas
+	InstallmentTxs(ctx context.Context, id uuid.UUID) ([]Transaction, error)
+	// SettleInstallment grava o novo status, remove as parcelas "remove" e grava "add" (se
+	// houver) atomicamente, desde que a compra ainda esteja ativa; senão ErrConflict
+	SettleInstallment(ctx context.Context, p *Installment, remove []uuid.UUID, add *Transaction) error
+	// DeleteInstallment remove a compra e todas as suas parcelas
+	DeleteInstallment(ctx context.Context, id uuid.UUID) error
+}
+
+// InstallmentInput descreve a compra: AmountCents é o total e OccurredAt a data da primeira
+// parcela; Type vazio equivale a despesa
+type InstallmentInput struct {
+	TxInput
+	Count     int
+	Remainder InstallmentRemainder // padrão RemainderFirst
+}
+
+// installmentAmounts divide total em n parcelas; o resto da divisão vai para a primeira ou
+// para a última
+func installmentAmounts(total int64, n int, rem InstallmentRemainder) []int64 {
+	out := make([]int64, n)
+	base, extra := total/int64(n), total%int64(n)
+	for i := range out {
+		out[i] = base
+	}
+	if rem == RemainderLast {
+		out[n-1] += extra
+	} else {
+		out[0] += extra
+	}
+	return out
+}
+
+// CreateInstallment lança as parcelas em meses sucessivos, no mesmo dia da primeira (ou no
+// último dia dos meses mais curtos). Só a primeira parcela está sujeita ao limite de datas
+// futuras.
+func (s *Service) CreateInstallment(ctx context.Context, in InstallmentInput) (*Installment, error) {
+	if in.Type == "" {
+		in.Type = Expense
+	}
+	if in.Type != Expense {
+		return nil, fmt.Errorf("%w: installment purchases must be expenses", ErrBadRequest)
+	}
+	if len(in.Splits) > 0 {
+		return nil, fmt.Errorf("%w: installment purchases cannot be split", ErrBadRequest)
+	}
+	if in.Count < 2 || in.Count > maxInstallments {
+		return nil, fmt.Errorf("%w: installments must be between 2 and %d", ErrBadRequest, maxInstallments)
+	}
+	if in.Remainder == "" {
+		in.Remainder = RemainderFirst
+	}
+	if in.Remainder != RemainderFirst && in.Remainder != RemainderLast {
+		return nil, fmt.Errorf("%w: remainder must be first or last", ErrBadRequest)
+	}
+	if in.AmountCents < int64(in.Count) {
+		return nil, fmt.Errorf("%w: amount_cents must be at least one cent per installment", ErrBadRequest)
+	}
+	amounts := installmentAmounts(in.AmountCents, in.Count, in.Remainder)
+
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	first := in.TxInput
+	first.AmountCents = amounts[0]
+	tx, err := s.newTx(ctx, first, now, cats)
+	if err != nil {
+		return nil, err
+	}
+	p := &Installment{
+		ID:          uuid.New(),
+		LedgerID:    ledgerScope(ctx),
+		Description: tx.Description,
+		TotalCents:  in.AmountCents,
+		Currency:    tx.Currency,
+		Count:       in.Count,
+		Remainder:   in.Remainder,
+		FirstAt:     tx.OccurredAt,
+		Status:      InstallmentActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	txs := make([]*Transaction, in.Count)
+	for i := range txs {
+		t := *tx
+		t.ID = uuid.New()
+		t.AmountCents = amounts[i]
+		t.OccurredAt = monthDay(tx.OccurredAt, i, tx.OccurredAt.Day())
+		t.InstallmentID = &p.ID
+		t.InstallmentNumber = i + 1
+		txs[i] = &t
+	}
+	if err := s.saveCategories(ctx, cats, txs...); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateInstallment(ctx, p, txs); err != nil {
+		return nil, err
+	}
+	p.Transactions = make([]Transaction, len(txs))
+	for i, t := range txs {
+		p.Transactions[i] = *t
+	}
+	return p, nil
+}
+
+// GetInstallment devolve a compra com as parcelas vinculadas
+func (s *Service) GetInstallment(ctx context.Context, id uuid.UUID) (*Installment, error) {
+	p, err := s.repo.GetInstallment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Transactions, err = s.repo.InstallmentTxs(ctx, id); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *Service) ListInstallments(ctx context.Context) ([]Installment, error) {
+	return s.repo.ListInstallments(ctx)
+}
+
+// DeleteInstallment remove a compra e todas as parcelas, inclusive as já vencidas
+func (s *Service) DeleteInstallment(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteInstallment(ctx, id)
+}
+
+// remainingInstallments separa as parcelas com data posterior a "at"
+func (s *Service) remainingInstallments(ctx context.Context, id uuid.UUID, at time.Time) (*Installment, []Transaction, error) {
+	p, err := s.repo.GetInstallment(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.Status != InstallmentActive {
+		return nil, nil, fmt.Errorf("%w: installment purchase is already %s", ErrConflict, p.Status)
+	}
+	txs, err := s.repo.InstallmentTxs(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rest []Transaction
+	for _, t := range txs {
+		if t.OccurredAt.After(at) {
+			rest = append(rest, t)
+		}
+	}
+	if len(rest) == 0 {
+		return nil, nil, fmt.Errorf("%w: no remaining installments", ErrBadRequest)
+	}
+	return p, rest, nil
+}
+
+// CancelInstallment remove as parcelas que ainda não venceram (ex: compra estornada); as
+// já lançadas são mantidas
+func (s *Service) CancelInstallment(ctx context.Context, id uuid.UUID) (*Installment, error) {
+	now := time.Now().UTC()
+	p, rest, err := s.remainingInstallments(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(rest))
+	for i, t := range rest {
+		ids[i] = t.ID
+	}
+	p.Status, p.UpdatedAt = InstallmentCancelled, now
+	if err := s.repo.SettleInstallment(ctx, p, ids, nil); err != nil {
+		return nil, err
+	}
+	return s.GetInstallment(ctx, id)
+}
+
+// PrepayInstallment antecipa as parcelas que ainda não venceram: elas são substituídas por
+// uma única despesa, hoje, com a soma dos valores
+func (s *Service) PrepayInstallment(ctx context.Context, id uuid.UUID) (*Installment, error) {
+	now := time.Now().UTC()
+	p, rest, err := s.remainingInstallments(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(rest))
+	var total int64
+	for i, t := range rest {
+		ids[i] = t.ID
+		total += t.AmountCents
+	}
+	tx := rest[0]
+	tx.ID = uuid.New()
+	tx.AmountCents = total
+	tx.OccurredAt = now
+	tx.CreatedAt, tx.UpdatedAt = now, now
+	p.Status, p.UpdatedAt = InstallmentPrepaid, now
+	if err := s.repo.SettleInstallment(ctx, p, ids, &tx); err != nil {
+		return nil, err
+	}
+	return s.GetInstallment(ctx, id)
+}
@@ -0,0 +1,70 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_Installments(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	now := time.Now().UTC()
+	// primeira parcela dois meses atrás: três vencidas e sete a vencer
+	first := time.Date(now.Year(), now.Month()-2, 1, 12, 0, 0, 0, time.UTC)
+	in := InstallmentInput{
+		TxInput: TxInput{Category: "Casa", AmountCents: 100001, Description: "geladeira", OccurredAt: first},
+		Count:   10,
+	}
+
+	p, err := s.CreateInstallment(ctx, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Transactions) != 10 || p.Transactions[0].AmountCents != 10001 || p.Transactions[9].AmountCents != 10000 {
+		t.Fatalf("unexpected installments: %+v", p.Transactions)
+	}
+	if last := p.Transactions[9]; last.InstallmentNumber != 10 || !last.OccurredAt.Equal(first.AddDate(0, 9, 0)) {
+		t.Errorf("last installment: %+v", last)
+	}
+	sum, err := s.MonthlySummary(ctx, first.Year(), int(first.Month()), TxFilter{}, "")
+	if err != nil || sum.Expense != 10001 {
+		t.Errorf("first month: %+v %v", sum, err)
+	}
+
+	in.Remainder = RemainderLast
+	other, err := s.CreateInstallment(ctx, in)
+	if err != nil || other.Transactions[0].AmountCents != 10000 || other.Transactions[9].AmountCents != 10001 {
+		t.Fatalf("remainder last: %+v %v", other, err)
+	}
+	in.Count = 1
+	if _, err := s.CreateInstallment(ctx, in); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("single installment: %v", err)
+	}
+
+	p, err = s.PrepayInstallment(ctx, p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Status != InstallmentPrepaid || len(p.Transactions) != 4 || p.Transactions[3].AmountCents != 70000 ||
+		p.Transactions[3].InstallmentNumber != 4 {
+		t.Errorf("prepaid: %+v", p)
+	}
+	if _, err := s.CancelInstallment(ctx, p.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("cancel after prepay: %v", err)
+	}
+
+	other, err = s.CancelInstallment(ctx, other.ID)
+	if err != nil || other.Status != InstallmentCancelled || len(other.Transactions) != 3 {
+		t.Errorf("cancelled: %+v %v", other, err)
+	}
+
+	if err := s.DeleteInstallment(ctx, other.ID); err != nil {
+		t.Fatal(err)
+	}
+	txs, _ := s.ListByPeriod(ctx, first, first.AddDate(1, 0, 0), TxFilter{})
+	if len(txs) != 4 {
+		t.Errorf("transactions left: %d", len(txs))
+	}
+}
@@ -0,0 +1,233 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrForbidden indica que o usuário não participa do livro ou não tem o papel exigido
+var ErrForbidden = errors.New("forbidden")
+
+// Role é o papel de um usuário em um livro; cada papel inclui as permissões do anterior
+type Role string
+
+const (
+	RoleViewer Role = "viewer" // leitura
+	RoleEditor Role = "editor" // cria, altera e remove lançamentos
+	RoleOwner  Role = "owner"  // administra os membros
+)
+
+var roleRank = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+func (r Role) Valid() bool { return roleRank[r] > 0 }
+
+// Allows indica se o papel satisfaz o mínimo exigido
+func (r Role) Allows(min Role) bool { return roleRank[r] >= roleRank[min] }
+
+// PersonalLedgerName é o nome do livro criado junto com cada usuário
+const PersonalLedgerName = "Pessoal"
+
+// Ledger é um livro-caixa (ex: a casa da família) compartilhado entre usuários.
+// Contas e transações pertencem a um livro.
+type Ledger struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role,omitempty"` // papel do usuário que consultou
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LedgerMember struct {
+	LedgerID  uuid.UUID `json:"ledger_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email,omitempty"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LedgerRepository interface {
+	// CreateLedger grava o livro e o usuário owner como primeiro membro
+	CreateLedger(ctx context.Context, l *Ledger, owner uuid.UUID) error
+	// ListLedgers lista os livros de que o usuário participa, com o papel dele
+	ListLedgers(ctx context.Context, user uuid.UUID) ([]Ledger, error)
+	GetMember(ctx context.Context, ledger, user uuid.UUID) (*LedgerMember, error)
+	ListMembers(ctx context.Context, ledger uuid.UUID) ([]LedgerMember, error)
+	// AddMember falha com ErrConflict se o usuário já for membro
+	AddMember(ctx context.Context, m *LedgerMember) error
+	UpdateMember(ctx context.Context, m *LedgerMember) error
+	RemoveMember(ctx context.Context, ledger, user uuid.UUID) error
+}
+
+// WithLedger devolve um contexto cujo escopo de dados é o livro id
+func WithLedger(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, ledgerCtxKey, id)
+}
+
+// LedgerFromContext devolve o livro em uso no contexto (ver ledgerScope)
+func LedgerFromContext(ctx context.Context) uuid.UUID { return ledgerScope(ctx) }
+
+// ledgerScope é o livro usado pelos repositórios para escopar leituras e escritas. Sem
+// livro no contexto vale o livro pessoal do usuário; sem usuário (uso interno, testes)
+// o escopo é uuid.Nil, que nenhum livro real possui.
+func ledgerScope(ctx context.Context) uuid.UUID {
+	if id, ok := ctx.Value(ledgerCtxKey).(uuid.UUID); ok {
+		return id
+	}
+	id, _ := UserFromContext(ctx)
+	return id
+}
+
+// currentUser devolve o usuário do contexto ou ErrUnauthorized
+func currentUser(ctx context.Context) (uuid.UUID, error) {
+	id, ok := UserFromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrUnauthorized
+	}
+	return id, nil
+}
+
+// Authorize confere se o usuário do contexto tem pelo menos o papel min no livro e
+// devolve o papel efetivo
+func (s *Service) Authorize(ctx context.Context, ledger uuid.UUID, min Role) (Role, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	m, err := s.repo.GetMember(ctx, ledger, user)
+	if errors.Is(err, ErrNotFound) {
+		return "", ErrForbidden
+	}
+	if err != nil {
+		return "", err
+	}
+	if !m.Role.Allows(min) {
+		return m.Role, ErrForbidden
+	}
+	return m.Role, nil
+}
+
+func (s *Service) CreateLedger(ctx context.Context, name string) (*Ledger, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrBadRequest)
+	}
+	l := &Ledger{ID: uuid.New(), Name: name, Role: RoleOwner, CreatedAt: time.Now().UTC()}
+	if err := s.repo.CreateLedger(ctx, l, user); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListLedgers lista os livros do usuário do contexto
+func (s *Service) ListLedgers(ctx context.Context) ([]Ledger, error) {
+	user, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListLedgers(ctx, user)
+}
+
+func (s *Service) ListMembers(ctx context.Context, ledger uuid.UUID) ([]LedgerMember, error) {
+	if _, err := s.Authorize(ctx, ledger, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(ctx, ledger)
+}
+
+// AddMember convida um usuário já cadastrado (pelo e-mail) para o livro; exige owner
+func (s *Service) AddMember(ctx context.Context, ledger uuid.UUID, email string, role Role) (*LedgerMember, error) {
+	if _, err := s.Authorize(ctx, ledger, RoleOwner); err != nil {
+		return nil, err
+	}
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: role must be owner, editor or viewer", ErrBadRequest)
+	}
+	email, _ = normalizeEmail(email)
+	u, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%w: unknown user", ErrBadRequest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := &LedgerMember{LedgerID: ledger, UserID: u.ID, Email: u.Email, Role: role, CreatedAt: time.Now().UTC()}
+	if err := s.repo.AddMember(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UpdateMember troca o papel de um membro; exige owner e mantém ao menos um owner
+func (s *Service) UpdateMember(ctx context.Context, ledger, user uuid.UUID, role Role) (*LedgerMember, error) {
+	if _, err := s.Authorize(ctx, ledger, RoleOwner); err != nil {
+		return nil, err
+	}
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: role must be owner, editor or viewer", ErrBadRequest)
+	}
+	m, err := s.repo.GetMember(ctx, ledger, user)
+	if err != nil {
+		return nil, err
+	}
+	if m.Role == RoleOwner && role != RoleOwner {
+		if err := s.keepOwner(ctx, ledger); err != nil {
+			return nil, err
+		}
+	}
+	m.Role = role
+	if err := s.repo.UpdateMember(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RemoveMember tira um membro do livro; exige owner, exceto para sair do próprio livro
+func (s *Service) RemoveMember(ctx context.Context, ledger, user uuid.UUID) error {
+	me, err := currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	min := RoleOwner
+	if user == me {
+		min = RoleViewer
+	}
+	if _, err := s.Authorize(ctx, ledger, min); err != nil {
+		return err
+	}
+	m, err := s.repo.GetMember(ctx, ledger, user)
+	if err != nil {
+		return err
+	}
+	if m.Role == RoleOwner {
+		if err := s.keepOwner(ctx, ledger); err != nil {
+			return err
+		}
+	}
+	return s.repo.RemoveMember(ctx, ledger, user)
+}
+
+// keepOwner falha se o livro ficaria sem nenhum owner
+func (s *Service) keepOwner(ctx context.Context, ledger uuid.UUID) error {
+	members, err := s.repo.ListMembers(ctx, ledger)
+	if err != nil {
+		return err
+	}
+	owners := 0
+	for _, m := range members {
+		if m.Role == RoleOwner {
+			owners++
+		}
+	}
+	if owners <= 1 {
+		return fmt.Errorf("%w: ledger must keep at least one owner", ErrConflict)
+	}
+	return nil
+}
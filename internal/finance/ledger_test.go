@@ -0,0 +1,71 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_SharedLedger(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	bg := context.Background()
+	ana, _ := s.Register(bg, "ana@example.com", "segredo-123")
+	bob, _ := s.Register(bg, "bob@example.com", "segredo-456")
+	actx := WithUser(bg, ana.ID)
+	bctx := WithUser(bg, bob.ID)
+
+	casa, err := s.CreateLedger(actx, "Casa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddMember(bctx, casa.ID, "bob@example.com", RoleEditor); !errors.Is(err, ErrForbidden) {
+		t.Errorf("non-member invited himself: %v", err)
+	}
+	if _, err := s.AddMember(actx, casa.ID, "bob@example.com", RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddMember(actx, casa.ID, "bob@example.com", RoleViewer); !errors.Is(err, ErrConflict) {
+		t.Errorf("duplicate member: %v", err)
+	}
+	if _, err := s.Authorize(bctx, casa.ID, RoleEditor); !errors.Is(err, ErrForbidden) {
+		t.Errorf("viewer authorized as editor: %v", err)
+	}
+	if role, err := s.Authorize(bctx, casa.ID, RoleViewer); err != nil || role != RoleViewer {
+		t.Errorf("viewer: %v %v", role, err)
+	}
+
+	// dados do livro compartilhado são vistos pelos membros, mas não no livro pessoal
+	if _, err := s.Create(WithLedger(actx, casa.ID), TxInput{Type: Expense, Category: "market", AmountCents: 9000}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	shared, _ := s.ListByPeriod(WithLedger(bctx, casa.ID), now.Add(-time.Hour), now.Add(time.Hour), TxFilter{})
+	personal, _ := s.ListByPeriod(actx, now.Add(-time.Hour), now.Add(time.Hour), TxFilter{})
+	if len(shared) != 1 || len(personal) != 0 {
+		t.Errorf("shared=%d personal=%d", len(shared), len(personal))
+	}
+	sum, err := s.MonthlySummary(WithLedger(bctx, casa.ID), now.Year(), int(now.Month()), TxFilter{}, "")
+	if err != nil || sum.Expense != 9000 {
+		t.Errorf("summary: %+v %v", sum, err)
+	}
+
+	ls, _ := s.ListLedgers(bctx)
+	if len(ls) != 2 {
+		t.Errorf("bob ledgers: %+v", ls)
+	}
+
+	// o único owner não pode sair nem ser rebaixado
+	if err := s.RemoveMember(actx, casa.ID, ana.ID); !errors.Is(err, ErrConflict) {
+		t.Errorf("last owner left: %v", err)
+	}
+	if _, err := s.UpdateMember(actx, casa.ID, bob.ID, RoleOwner); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.UpdateMember(actx, casa.ID, ana.ID, RoleViewer); err != nil {
+		t.Errorf("demote with another owner: %v", err)
+	}
+	if err := s.RemoveMember(actx, casa.ID, ana.ID); err != nil {
+		t.Errorf("member leaving: %v", err)
+	}
+}
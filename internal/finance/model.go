@@ -1,6 +1,10 @@
 package finance
 
 import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,28 +13,126 @@ import (
 type TxType string
 
 const (
-	Income  TxType = "income"
-	Expense TxType = "expense"
+	Income   TxType = "income"
+	Expense  TxType = "expense"
+	Transfer TxType = "transfer"
+)
+
+// TransferDirection indica se a perna da transferência sai ou entra na conta
+type TransferDirection string
+
+const (
+	TransferOut TransferDirection = "out"
+	TransferIn  TransferDirection = "in"
 )
 
 type Transaction struct {
-	ID          uuid.UUID `json:"id"`
-	Type        TxType    `json:"type"`         // "income" | "expense"
-	Category    string    `json:"category"`     // ex: salary, rent, food
-	AmountCents int64     `json:"amount_cents"` // ex: 12345 = R$ 123,45
-	OccurredAt  time.Time `json:"occurred_at"`
-	Description string    `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                uuid.UUID         `json:"id"`
+	LedgerID          uuid.UUID         `json:"-"`
+	Type              TxType            `json:"type"`         // "income" | "expense" | "transfer"
+	Category          string            `json:"category"`     // ex: salary, rent, food
+	AmountCents       int64             `json:"amount_cents"` // ex: 12345 = R$ 123,45
+	Currency          string            `json:"currency"`     // ISO 4217, ex: BRL
+	OccurredAt        time.Time         `json:"occurred_at"`
+	Description       string            `json:"description,omitempty"`
+	AccountID         *uuid.UUID        `json:"account_id,omitempty"`
+	TransferID        *uuid.UUID        `json:"transfer_id,omitempty"`        // compartilhado pelas duas pernas
+	Direction         TransferDirection `json:"direction,omitempty"`          // "out" | "in" (apenas transferências)
+	ExternalID        string            `json:"external_id,omitempty"`        // id do banco (FITID do OFX), único por conta
+	InstallmentID     *uuid.UUID        `json:"installment_id,omitempty"`     // compra parcelada de origem
+	InstallmentNumber int               `json:"installment_number,omitempty"` // 1..N
+	Statement         string            `json:"statement,omitempty"`          // fatura do cartão paga por esta transferência (YYYY-MM)
+	Tags              []string          `json:"tags,omitempty"`               // minúsculas, ordenadas
+	Splits            []Split           `json:"splits,omitempty"`             // rateio entre categorias
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
 }
 
 type MonthlySummary struct {
-	Year        int   `json:"year"`
-	Month       int   `json:"month"`
-	Income      int64 `json:"income_cents"`
-	Expense     int64 `json:"expense_cents"`
-	Net         int64 `json:"net_cents"`
-	CountTx     int   `json:"count_transactions"`
-	FirstTxDate string `json:"first_tx,omitempty"`
-	LastTxDate  string `json:"last_tx,omitempty"`
-}
\ No newline at end of file
+	Year        int             `json:"year"`
+	Month       int             `json:"month"`
+	Income      int64           `json:"income_cents"`
+	Expense     int64           `json:"expense_cents"`
+	Net         int64           `json:"net_cents"`
+	Currency    string          `json:"currency"` // moeda base da conversão
+	CountTx     int             `json:"count_transactions"`
+	FirstTxDate string          `json:"first_tx,omitempty"`
+	LastTxDate  string          `json:"last_tx,omitempty"`
+	Categories  []CategoryTotal `json:"categories"` // receitas e despesas por categoria
+}
+
+// CategoryTotal é o total de uma categoria dentro de um tipo (receita ou despesa)
+type CategoryTotal struct {
+	Type       TxType  `json:"type"`
+	Category   string  `json:"category"`
+	Path       string  `json:"path,omitempty"` // caminho na hierarquia, se a categoria é gerenciada
+	TotalCents int64   `json:"total_cents"`
+	Count      int     `json:"count"`
+	Percent    float64 `json:"percent"` // do total do tipo, com duas casas
+}
+
+func (ms *MonthlySummary) finishCategories() {
+	ms.Categories = finishCategories(ms.Categories, ms.Income, ms.Expense)
+}
+
+// finishCategories ordena o detalhamento (receitas e depois despesas, maiores primeiro)
+// e calcula o percentual de cada categoria sobre o total do tipo
+func finishCategories(cats []CategoryTotal, income, expense int64) []CategoryTotal {
+	if cats == nil {
+		cats = []CategoryTotal{}
+	}
+	for i := range cats {
+		c := &cats[i]
+		total := income
+		if c.Type == Expense {
+			total = expense
+		}
+		if total != 0 {
+			c.Percent = math.Round(float64(c.TotalCents)*10000/float64(total)) / 100
+		}
+	}
+	slices.SortFunc(cats, func(a, b CategoryTotal) int {
+		if a.Type != b.Type {
+			return strings.Compare(string(b.Type), string(a.Type)) // income antes de expense
+		}
+		if a.TotalCents != b.TotalCents {
+			return cmp.Compare(b.TotalCents, a.TotalCents)
+		}
+		return strings.Compare(a.Category, b.Category)
+	})
+	return cats
+}
+
+// TxFilter restringe listagens e resumos; campos vazios/nil não filtram
+type TxFilter struct {
+	AccountID   *uuid.UUID
+	Type        TxType
+	Category    string   // também casa com as linhas do rateio; nos resumos só elas contam
+	MinAmount   *int64   // centavos, inclusive
+	MaxAmount   *int64   // centavos, inclusive
+	Description string   // substring, sem diferenciar maiúsculas
+	Tags        []string // ver TagMatch
+	TagMatch    TagMatch // padrão TagsAny
+}
+
+func (f TxFilter) matches(t *Transaction) bool {
+	if f.AccountID != nil && (t.AccountID == nil || *t.AccountID != *f.AccountID) {
+		return false
+	}
+	if f.Type != "" && t.Type != f.Type {
+		return false
+	}
+	if f.Category != "" && !t.hasCategory(f.Category) {
+		return false
+	}
+	if f.MinAmount != nil && t.AmountCents < *f.MinAmount {
+		return false
+	}
+	if f.MaxAmount != nil && t.AmountCents > *f.MaxAmount {
+		return false
+	}
+	if f.Description != "" && !strings.Contains(strings.ToLower(t.Description), strings.ToLower(f.Description)) {
+		return false
+	}
+	return f.matchTags(t.Tags)
+}
@@ -0,0 +1,189 @@
+package finance
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TxSort é o campo de ordenação da listagem paginada
+type TxSort string
+
+const (
+	SortOccurredAt TxSort = "occurred_at"
+	SortAmount     TxSort = "amount"
+	SortCategory   TxSort = "category"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// TxQuery descreve uma página de transações. Cursor é o valor opaco devolvido em
+// TxPage.NextCursor; ele só é válido para a mesma combinação de Sort e Desc.
+type TxQuery struct {
+	From   time.Time
+	To     time.Time
+	Filter TxFilter
+	Sort   TxSort
+	Desc   bool
+	Limit  int
+	Cursor string
+
+	// After é o cursor decodificado, preenchido pelo Service para o repositório
+	After *PageCursor
+}
+
+// PageCursor é a chave (valor de ordenação, id) do último item entregue
+type PageCursor struct {
+	Sort  TxSort    `json:"s"`
+	Desc  bool      `json:"d,omitempty"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+type TxPage struct {
+	Items      []Transaction `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+func encodeCursor(c PageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*PageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+	}
+	var c PageCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+	}
+	return &c, nil
+}
+
+// sortValue devolve o valor de ordenação da transação no formato guardado no cursor
+func sortValue(t *Transaction, s TxSort) string {
+	switch s {
+	case SortAmount:
+		return strconv.FormatInt(t.AmountCents, 10)
+	case SortCategory:
+		return t.Category
+	default:
+		return t.OccurredAt.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// compareTx ordena por (campo, id) em ordem crescente, igual ao ORDER BY do pgRepo
+func compareTx(a, b *Transaction, s TxSort) int {
+	var c int
+	switch s {
+	case SortAmount:
+		c = cmpInt64(a.AmountCents, b.AmountCents)
+	case SortCategory:
+		c = strings.Compare(a.Category, b.Category)
+	default:
+		c = a.OccurredAt.Compare(b.OccurredAt)
+	}
+	if c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+func cmpInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// cursorTx reconstrói uma transação "fantasma" com a chave do cursor para comparação
+func cursorTx(c *PageCursor) (*Transaction, error) {
+	t := &Transaction{ID: c.ID}
+	switch c.Sort {
+	case SortAmount:
+		v, err := strconv.ParseInt(c.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+		}
+		t.AmountCents = v
+	case SortCategory:
+		t.Category = c.Value
+	default:
+		v, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor", ErrBadRequest)
+		}
+		t.OccurredAt = v
+	}
+	return t, nil
+}
+
+// List devolve uma página de transações do período com filtros e ordenação
+func (s *Service) List(ctx context.Context, q TxQuery) (*TxPage, error) {
+	if q.To.Before(q.From) {
+		return nil, ErrBadRequest
+	}
+	switch q.Sort {
+	case "":
+		q.Sort = SortOccurredAt
+	case SortOccurredAt, SortAmount, SortCategory:
+	default:
+		return nil, fmt.Errorf("%w: invalid sort %q", ErrBadRequest, q.Sort)
+	}
+	if err := q.Filter.normalizeTags(); err != nil {
+		return nil, err
+	}
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultPageLimit
+	case q.Limit < 0 || q.Limit > MaxPageLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrBadRequest, MaxPageLimit)
+	}
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != q.Sort || c.Desc != q.Desc {
+			return nil, fmt.Errorf("%w: cursor does not match sort order", ErrBadRequest)
+		}
+		if _, err := cursorTx(c); err != nil {
+			return nil, err
+		}
+		q.After = c
+	}
+	q.From, q.To = q.From.UTC(), q.To.UTC()
+
+	// pede um item a mais para saber se existe próxima página
+	limit := q.Limit
+	q.Limit++
+	items, err := s.repo.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	page := &TxPage{Items: items}
+	if page.Items == nil {
+		page.Items = []Transaction{}
+	}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := &page.Items[limit-1]
+		page.NextCursor = encodeCursor(PageCursor{Sort: q.Sort, Desc: q.Desc, Value: sortValue(last, q.Sort), ID: last.ID})
+	}
+	return page, nil
+}
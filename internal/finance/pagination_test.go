@@ -0,0 +1,60 @@
+package finance
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestList_PaginatesWithCursor(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+
+	base := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		in := TxInput{Type: Expense, Category: "food", AmountCents: int64(100 * (i%3 + 1)), OccurredAt: base.Add(time.Duration(i) * time.Hour)}
+		if i == 6 {
+			in.Category, in.Description = "rent", "Aluguel Março"
+		}
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	q := TxQuery{From: base, To: base.AddDate(0, 1, 0), Sort: SortAmount, Desc: true, Limit: 3}
+	var seen []Transaction
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := s.List(ctx, q)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		seen = append(seen, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	if len(seen) != 7 {
+		t.Fatalf("expected 7 items across pages, got %d", len(seen))
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i].AmountCents > seen[i-1].AmountCents {
+			t.Fatalf("not sorted desc at %d: %d > %d", i, seen[i].AmountCents, seen[i-1].AmountCents)
+		}
+		if seen[i].ID == seen[i-1].ID {
+			t.Fatalf("duplicate item across pages")
+		}
+	}
+
+	page, err := s.List(ctx, TxQuery{From: base, To: base.AddDate(0, 1, 0), Filter: TxFilter{Description: "alUGUEL"}})
+	if err != nil || len(page.Items) != 1 || page.Items[0].Category != "rent" {
+		t.Fatalf("description filter: err=%v page=%+v", err, page)
+	}
+
+	if _, err := s.List(ctx, TxQuery{From: base, To: base, Sort: SortCategory, Cursor: q.Cursor}); err == nil {
+		t.Fatal("expected error for cursor from a different sort")
+	}
+}
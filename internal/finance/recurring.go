@@ -0,0 +1,360 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Frequency é a periodicidade de uma regra recorrente
+type Frequency string
+
+const (
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+	Yearly  Frequency = "yearly"
+)
+
+// maxRecurringCatchUp limita quantas ocorrências atrasadas uma regra lança por execução
+const maxRecurringCatchUp = 100
+
+// RecurringRule gera uma transação a cada ocorrência, no estilo RRULE: a cada Interval
+// semanas, meses ou anos desde StartAt, até Until e/ou Count ocorrências. Regras mensais
+// caem no dia DayOfMonth (ou no último dia dos meses mais curtos).
+type RecurringRule struct {
+	ID          uuid.UUID  `json:"id"`
+	LedgerID    uuid.UUID  `json:"-"`
+	Type        TxType     `json:"type"`
+	Category    string     `json:"category"`
+	AmountCents int64      `json:"amount_cents"`
+	Currency    string     `json:"currency"`
+	Description string     `json:"description,omitempty"`
+	AccountID   *uuid.UUID `json:"account_id,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Frequency   Frequency  `json:"frequency"`
+	Interval    int        `json:"interval"`
+	DayOfMonth  int        `json:"day_of_month,omitempty"`
+	StartAt     time.Time  `json:"start_at"`
+	Until       *time.Time `json:"until,omitempty"`
+	Count       int        `json:"count,omitempty"` // 0 = sem limite; ocorrências puladas contam
+	Skipped     []string   `json:"skipped"`         // datas (YYYY-MM-DD) que não serão lançadas
+	Done        int        `json:"occurrences_done"`
+	NextAt      *time.Time `json:"next_at,omitempty"` // nil quando a regra terminou
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type RecurringRepository interface {
+	CreateRecurring(ctx context.Context, r *RecurringRule) error
+	GetRecurring(ctx context.Context, id uuid.UUID) (*RecurringRule, error)
+	ListRecurring(ctx context.Context) ([]RecurringRule, error)
+	// UpdateRecurring grava as datas puladas da regra
+	UpdateRecurring(ctx context.Context, r *RecurringRule) error
+	DeleteRecurring(ctx context.Context, id uuid.UUID) error
+	// DueRecurring lista as regras de todos os livros com ocorrência pendente até "at"
+	DueRecurring(ctx context.Context, at time.Time) ([]RecurringRule, error)
+	// AdvanceRecurring grava tx (nil para ocorrência pulada) e o novo estado da regra na
+	// mesma transação, desde que a regra ainda esteja em prevDone; senão ErrConflict
+	AdvanceRecurring(ctx context.Context, r *RecurringRule, prevDone int, tx *Transaction) error
+	// RunExclusive executa fn só se nenhuma outra instância estiver com o lock "name";
+	// ran=false indica que o lock estava ocupado
+	RunExclusive(ctx context.Context, name string, fn func(context.Context) error) (ran bool, err error)
+}
+
+type RecurringInput struct {
+	TxInput    // OccurredAt é ignorado
+	Frequency  Frequency
+	Interval   int // padrão 1
+	DayOfMonth int // mensal; padrão é o dia de StartAt
+	StartAt    time.Time
+	Until      *time.Time
+	Count      int
+}
+
+// RecurringOccurrence é uma ocorrência futura de uma regra
+type RecurringOccurrence struct {
+	RuleID      uuid.UUID `json:"rule_id"`
+	At          time.Time `json:"at"`
+	Type        TxType    `json:"type"`
+	Category    string    `json:"category"`
+	AmountCents int64     `json:"amount_cents"`
+	Currency    string    `json:"currency"`
+	Description string    `json:"description,omitempty"`
+	Skipped     bool      `json:"skipped"`
+}
+
+// occurrence devolve a n-ésima ocorrência (a partir de 0); ok=false depois do fim da regra
+func (r *RecurringRule) occurrence(n int) (at time.Time, ok bool) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+	s := r.StartAt
+	switch r.Frequency {
+	case Weekly:
+		at = s.AddDate(0, 0, 7*r.Interval*n)
+	case Monthly:
+		first := 0
+		if monthDay(s, 0, r.DayOfMonth).Before(s) {
+			first = 1
+		}
+		at = monthDay(s, first+n*r.Interval, r.DayOfMonth)
+	case Yearly:
+		at = monthDay(s, 12*r.Interval*n, s.Day())
+	}
+	if r.Until != nil && at.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// monthDay devolve o dia "day" do mês de s somado a "months", limitado ao último dia do
+// mês e com o horário de s
+func monthDay(s time.Time, months, day int) time.Time {
+	first := time.Date(s.Year(), s.Month()+time.Month(months), 1, s.Hour(), s.Minute(), s.Second(), 0, time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// advance marca a ocorrência atual como processada e recalcula NextAt
+func (r *RecurringRule) advance() {
+	r.Done++
+	r.NextAt = nil
+	if at, ok := r.occurrence(r.Done); ok {
+		r.NextAt = &at
+	}
+}
+
+func (r *RecurringRule) skipped(at time.Time) bool {
+	return slices.Contains(r.Skipped, at.Format("2006-01-02"))
+}
+
+// txInput monta a transação da ocorrência "at"
+func (r *RecurringRule) txInput(at time.Time) TxInput {
+	return TxInput{
+		Type:        r.Type,
+		Category:    r.Category,
+		AmountCents: r.AmountCents,
+		Currency:    r.Currency,
+		Description: r.Description,
+		OccurredAt:  at,
+		AccountID:   r.AccountID,
+		Tags:        r.Tags,
+	}
+}
+
+func (s *Service) CreateRecurring(ctx context.Context, in RecurringInput) (*RecurringRule, error) {
+	now := time.Now().UTC()
+	r := &RecurringRule{
+		ID:         uuid.New(),
+		LedgerID:   ledgerScope(ctx),
+		Frequency:  in.Frequency,
+		Interval:   in.Interval,
+		DayOfMonth: in.DayOfMonth,
+		StartAt:    in.StartAt.UTC(),
+		Until:      in.Until,
+		Count:      in.Count,
+		Skipped:    []string{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if len(in.Splits) > 0 {
+		return nil, fmt.Errorf("%w: recurring rules cannot be split", ErrBadRequest)
+	}
+	switch r.Frequency {
+	case Weekly, Monthly, Yearly:
+	default:
+		return nil, fmt.Errorf("%w: frequency must be weekly, monthly or yearly", ErrBadRequest)
+	}
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if in.StartAt.IsZero() {
+		r.StartAt = now
+	}
+	if r.DayOfMonth == 0 {
+		r.DayOfMonth = r.StartAt.Day()
+	}
+	switch {
+	case r.Interval < 0 || r.Interval > 100:
+		return nil, fmt.Errorf("%w: interval must be between 1 and 100", ErrBadRequest)
+	case r.DayOfMonth < 1 || r.DayOfMonth > 31:
+		return nil, fmt.Errorf("%w: day_of_month must be between 1 and 31", ErrBadRequest)
+	case r.Frequency != Monthly && in.DayOfMonth != 0:
+		return nil, fmt.Errorf("%w: day_of_month only applies to monthly rules", ErrBadRequest)
+	case r.Count < 0:
+		return nil, fmt.Errorf("%w: count must not be negative", ErrBadRequest)
+	}
+	if r.Frequency != Monthly {
+		r.DayOfMonth = 0
+	}
+	if r.Until != nil {
+		u := r.Until.UTC()
+		r.Until = &u
+	}
+
+	// valida o modelo com as regras de Create (categoria, conta e moeda)
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tin := in.TxInput
+	tin.OccurredAt = now
+	tx, err := s.newTx(ctx, tin, now, cats)
+	if err != nil {
+		return nil, err
+	}
+	r.Type, r.Category, r.AmountCents = tx.Type, tx.Category, tx.AmountCents
+	r.Currency, r.Description, r.AccountID = tx.Currency, tx.Description, tx.AccountID
+	r.Tags = tx.Tags
+	if at, ok := r.occurrence(0); ok {
+		r.NextAt = &at
+	} else {
+		return nil, fmt.Errorf("%w: rule has no occurrences", ErrBadRequest)
+	}
+	if err := s.saveCategories(ctx, cats, tx); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRecurring(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *Service) GetRecurring(ctx context.Context, id uuid.UUID) (*RecurringRule, error) {
+	return s.repo.GetRecurring(ctx, id)
+}
+
+func (s *Service) ListRecurring(ctx context.Context) ([]RecurringRule, error) {
+	return s.repo.ListRecurring(ctx)
+}
+
+// DeleteRecurring encerra a regra; transações já lançadas são mantidas
+func (s *Service) DeleteRecurring(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteRecurring(ctx, id)
+}
+
+// SkipOccurrence faz com que a ocorrência do dia "date" não seja lançada
+func (s *Service) SkipOccurrence(ctx context.Context, id uuid.UUID, date time.Time) (*RecurringRule, error) {
+	r, err := s.repo.GetRecurring(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	day := date.UTC().Format("2006-01-02")
+	for n := r.Done; ; n++ {
+		at, ok := r.occurrence(n)
+		if !ok || at.Format("2006-01-02") > day {
+			return nil, fmt.Errorf("%w: %s is not a pending occurrence of this rule", ErrBadRequest, day)
+		}
+		if at.Format("2006-01-02") == day {
+			break
+		}
+	}
+	if r.skipped(date.UTC()) {
+		return r, nil
+	}
+	r.Skipped = append(r.Skipped, day)
+	slices.Sort(r.Skipped)
+	r.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpdateRecurring(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UpcomingOccurrences lista as ocorrências pendentes das regras do livro até "until"
+func (s *Service) UpcomingOccurrences(ctx context.Context, until time.Time) ([]RecurringOccurrence, error) {
+	rules, err := s.repo.ListRecurring(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := []RecurringOccurrence{}
+	for _, r := range rules {
+		for n := r.Done; ; n++ {
+			at, ok := r.occurrence(n)
+			if !ok || at.After(until) {
+				break
+			}
+			out = append(out, RecurringOccurrence{
+				RuleID:      r.ID,
+				At:          at,
+				Type:        r.Type,
+				Category:    r.Category,
+				AmountCents: r.AmountCents,
+				Currency:    r.Currency,
+				Description: r.Description,
+				Skipped:     r.skipped(at),
+			})
+		}
+	}
+	slices.SortFunc(out, func(a, b RecurringOccurrence) int { return a.At.Compare(b.At) })
+	return out, nil
+}
+
+// RunRecurring lança as ocorrências vencidas até "now" de todos os livros. Só uma instância
+// executa por vez (lock no repositório) e cada ocorrência é gravada junto com o avanço da
+// regra, então nenhuma é lançada duas vezes. Devolve quantas transações foram criadas;
+// regras com erro (ex: conta removida) são reportadas e tentadas de novo na próxima execução.
+func (s *Service) RunRecurring(ctx context.Context, now time.Time) (int, error) {
+	created := 0
+	var errs []error
+	_, err := s.repo.RunExclusive(ctx, "recurring", func(ctx context.Context) error {
+		rules, err := s.repo.DueRecurring(ctx, now)
+		if err != nil {
+			return err
+		}
+		for _, r := range rules {
+			n, err := s.materialize(WithLedger(ctx, r.LedgerID), &r, now)
+			created += n
+			if err != nil {
+				errs = append(errs, fmt.Errorf("recurring rule %s: %w", r.ID, err))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return created, err
+	}
+	return created, errors.Join(errs...)
+}
+
+// materialize lança as ocorrências vencidas de uma regra com as regras de Create
+func (s *Service) materialize(ctx context.Context, r *RecurringRule, now time.Time) (int, error) {
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for range maxRecurringCatchUp {
+		at, ok := r.occurrence(r.Done)
+		if !ok || at.After(now) {
+			break
+		}
+		var tx *Transaction
+		if !r.skipped(at) {
+			if tx, err = s.newTx(ctx, r.txInput(at), now, cats); err != nil {
+				return created, err
+			}
+			if err := s.saveCategories(ctx, cats, tx); err != nil {
+				return created, err
+			}
+		}
+		prev := r.Done
+		r.advance()
+		r.UpdatedAt = now
+		err := s.repo.AdvanceRecurring(ctx, r, prev, tx)
+		if errors.Is(err, ErrConflict) {
+			return created, nil // outra execução já avançou a regra
+		}
+		if err != nil {
+			return created, err
+		}
+		if tx != nil {
+			created++
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,90 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecurringRule_Occurrences(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 9, 0, 0, 0, time.UTC) }
+	until := day(2025, 4, 30)
+	cases := []struct {
+		name string
+		rule RecurringRule
+		want []time.Time
+	}{
+		{"monthly clamps to month end", RecurringRule{Frequency: Monthly, Interval: 1, DayOfMonth: 31, StartAt: day(2025, 1, 31), Until: &until},
+			[]time.Time{day(2025, 1, 31), day(2025, 2, 28), day(2025, 3, 31), day(2025, 4, 30)}},
+		{"monthly day before start", RecurringRule{Frequency: Monthly, Interval: 2, DayOfMonth: 5, StartAt: day(2025, 1, 10), Count: 2},
+			[]time.Time{day(2025, 2, 5), day(2025, 4, 5)}},
+		{"weekly", RecurringRule{Frequency: Weekly, Interval: 1, StartAt: day(2025, 1, 1), Count: 3},
+			[]time.Time{day(2025, 1, 1), day(2025, 1, 8), day(2025, 1, 15)}},
+		{"yearly leap day", RecurringRule{Frequency: Yearly, Interval: 1, StartAt: day(2024, 2, 29), Count: 2},
+			[]time.Time{day(2024, 2, 29), day(2025, 2, 28)}},
+	}
+	for _, c := range cases {
+		var got []time.Time
+		for n := 0; n < 10; n++ {
+			at, ok := c.rule.occurrence(n)
+			if !ok {
+				break
+			}
+			got = append(got, at)
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("%s: got %v", c.name, got)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(c.want[i]) {
+				t.Errorf("%s[%d]: got %v, want %v", c.name, i, got[i], c.want[i])
+			}
+		}
+	}
+}
+
+func TestService_RunRecurring(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	start := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	r, err := s.CreateRecurring(ctx, RecurringInput{
+		TxInput:   TxInput{Type: Expense, Category: "Moradia > Aluguel", AmountCents: 150000, Description: "aluguel"},
+		Frequency: Monthly,
+		StartAt:   start,
+		Count:     4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SkipOccurrence(ctx, r.ID, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SkipOccurrence(ctx, r.ID, time.Date(2025, 3, 30, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("skip of a non-occurrence: %v", err)
+	}
+	up, err := s.UpcomingOccurrences(ctx, time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	if err != nil || len(up) != 4 || !up[2].Skipped || up[1].At.Day() != 28 {
+		t.Errorf("upcoming: %+v %v", up, err)
+	}
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	n, err := s.RunRecurring(ctx, now)
+	if err != nil || n != 3 {
+		t.Fatalf("first run: n=%d err=%v", n, err)
+	}
+	// execuções repetidas não duplicam
+	if n, err := s.RunRecurring(ctx, now); err != nil || n != 0 {
+		t.Errorf("second run: n=%d err=%v", n, err)
+	}
+	txs, _ := s.ListByPeriod(ctx, start, now, TxFilter{})
+	if len(txs) != 3 || txs[0].Category != "Aluguel" {
+		t.Errorf("materialized: %+v", txs)
+	}
+	r, _ = s.GetRecurring(ctx, r.ID)
+	if r.Done != 4 || r.NextAt != nil {
+		t.Errorf("rule not finished: %+v", r)
+	}
+}
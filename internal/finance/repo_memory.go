@@ -2,6 +2,7 @@ package finance
 
 import (
 	"context"
+	"fmt"
 	"slices"
 	"sync"
 	"time"
@@ -10,12 +11,39 @@ import (
 )
 
 type memoryRepo struct {
-	mu   sync.RWMutex
-	data map[uuid.UUID]*Transaction
+	mu          sync.RWMutex
+	exclusive   sync.Mutex // lock de RunExclusive
+	data        map[uuid.UUID]*Transaction
+	accounts    map[uuid.UUID]*Account
+	rates       map[uuid.UUID]rateBook // por livro
+	idempotency map[idempotencyKey]*IdempotencyRecord
+	users       map[uuid.UUID]*User
+	ledgers     map[uuid.UUID]*Ledger
+	members     map[memberKey]*LedgerMember
+	apiTokens   map[uuid.UUID]*APIToken
+	categories  map[uuid.UUID]*Category
+	budgets     map[uuid.UUID]*Budget
+	recurring   map[uuid.UUID]*RecurringRule
+	purchases   map[uuid.UUID]*Installment
+	goals       map[uuid.UUID]*Goal
 }
 
 func NewMemoryRepo() Repository {
-	return &memoryRepo{data: make(map[uuid.UUID]*Transaction)}
+	return &memoryRepo{
+		data:        make(map[uuid.UUID]*Transaction),
+		accounts:    make(map[uuid.UUID]*Account),
+		rates:       make(map[uuid.UUID]rateBook),
+		idempotency: make(map[idempotencyKey]*IdempotencyRecord),
+		users:       make(map[uuid.UUID]*User),
+		ledgers:     make(map[uuid.UUID]*Ledger),
+		members:     make(map[memberKey]*LedgerMember),
+		apiTokens:   make(map[uuid.UUID]*APIToken),
+		categories:  make(map[uuid.UUID]*Category),
+		budgets:     make(map[uuid.UUID]*Budget),
+		recurring:   make(map[uuid.UUID]*RecurringRule),
+		purchases:   make(map[uuid.UUID]*Installment),
+		goals:       make(map[uuid.UUID]*Goal),
+	}
 }
 
 func (m *memoryRepo) Create(ctx context.Context, t *Transaction) error {
@@ -26,12 +54,67 @@ func (m *memoryRepo) Create(ctx context.Context, t *Transaction) error {
 	return nil
 }
 
-func (m *memoryRepo) ListByPeriod(ctx context.Context, from, to time.Time) ([]Transaction, error) {
+func (m *memoryRepo) CreateExternal(ctx context.Context, t *Transaction) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t.ExternalID != "" {
+		for _, v := range m.data {
+			if v.LedgerID == t.LedgerID && v.ExternalID == t.ExternalID && sameAccount(v.AccountID, t.AccountID) {
+				return false, nil
+			}
+		}
+	}
+	cp := *t
+	m.data[t.ID] = &cp
+	return true, nil
+}
+
+func sameAccount(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (m *memoryRepo) CreateBatch(ctx context.Context, ts []*Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, t := range ts {
+		cp := *t
+		m.data[t.ID] = &cp
+	}
+	return nil
+}
+
+func (m *memoryRepo) Get(ctx context.Context, id uuid.UUID) (*Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return nil, ErrNotFound
+	}
+	cp := *v
+	return &cp, nil
+}
+
+func (m *memoryRepo) Update(ctx context.Context, t *Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.data[t.ID]; !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	cp := *t
+	m.data[t.ID] = &cp
+	return nil
+}
+
+func (m *memoryRepo) ListByPeriod(ctx context.Context, from, to time.Time, f TxFilter) ([]Transaction, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
 	var out []Transaction
 	for _, v := range m.data {
-		if !v.OccurredAt.Before(from) && !v.OccurredAt.After(to) {
+		if v.LedgerID == own && !v.OccurredAt.Before(from) && !v.OccurredAt.After(to) && f.matches(v) {
 			out = append(out, *v)
 		}
 	}
@@ -41,30 +124,91 @@ func (m *memoryRepo) ListByPeriod(ctx context.Context, from, to time.Time) ([]Tr
 	return out, nil
 }
 
+func (m *memoryRepo) CreateTransfer(ctx context.Context, out, in *Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	o, i := *out, *in
+	m.data[out.ID] = &o
+	m.data[in.ID] = &i
+	return nil
+}
+
+func (m *memoryRepo) List(ctx context.Context, q TxQuery) ([]Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var after *Transaction
+	if q.After != nil {
+		var err error
+		if after, err = cursorTx(q.After); err != nil {
+			return nil, err
+		}
+	}
+	cmp := func(a, b *Transaction) int {
+		c := compareTx(a, b, q.Sort)
+		if q.Desc {
+			return -c
+		}
+		return c
+	}
+	own := ledgerScope(ctx)
+	var out []Transaction
+	for _, v := range m.data {
+		if v.LedgerID != own || v.OccurredAt.Before(q.From) || v.OccurredAt.After(q.To) || !q.Filter.matches(v) {
+			continue
+		}
+		if after != nil && cmp(v, after) <= 0 {
+			continue
+		}
+		out = append(out, *v)
+	}
+	slices.SortFunc(out, func(a, b Transaction) int { return cmp(&a, &b) })
+	if len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+// Delete remove a transação; se for perna de transferência, remove as duas
 func (m *memoryRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if _, ok := m.data[id]; !ok {
+	v, ok := m.data[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
 		return ErrNotFound
 	}
+	if v.TransferID != nil {
+		for k, o := range m.data {
+			if o.TransferID != nil && *o.TransferID == *v.TransferID {
+				delete(m.data, k)
+			}
+		}
+	}
 	delete(m.data, id)
 	return nil
 }
 
-func (m *memoryRepo) MonthlySummary(ctx context.Context, year int, month int) (*MonthlySummary, error) {
+func (m *memoryRepo) MonthlySummary(ctx context.Context, year int, month int, f TxFilter, base string) (*MonthlySummary, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	var inc, exp int64
 	var cnt int
 	var first, last *time.Time
+	byCat := categoryAcc{}
+	own := ledgerScope(ctx)
 	for _, v := range m.data {
-		if v.OccurredAt.Year() == year && int(v.OccurredAt.Month()) == month {
+		if v.LedgerID == own && v.Type != Transfer && v.OccurredAt.Year() == year && int(v.OccurredAt.Month()) == month && f.matches(v) {
+			v := f.summaryPart(v)
+			amt, ok := m.convert(v, base)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s to %s on %s", ErrMissingRate, v.Currency, base, v.OccurredAt.Format("2006-01-02"))
+			}
 			if v.Type == Income {
-				inc += v.AmountCents
+				inc += amt
 			} else if v.Type == Expense {
-				exp += v.AmountCents
+				exp += amt
 			}
 			cnt++
+			byCat.add(m, v, base)
 			if first == nil || v.OccurredAt.Before(*first) {
 				t := v.OccurredAt
 				first = &t
@@ -75,10 +219,44 @@ func (m *memoryRepo) MonthlySummary(ctx context.Context, year int, month int) (*
 			}
 		}
 	}
-	ms := &MonthlySummary{Year: year, Month: month, Income: inc, Expense: exp, Net: inc - exp, CountTx: cnt}
+	ms := &MonthlySummary{Year: year, Month: month, Income: inc, Expense: exp, Net: inc - exp, Currency: base, CountTx: cnt}
 	if first != nil {
 		ms.FirstTxDate = first.Format(time.RFC3339)
 		ms.LastTxDate = last.Format(time.RFC3339)
 	}
+	ms.Categories = byCat.list()
+	ms.finishCategories()
 	return ms, nil
-}
\ No newline at end of file
+}
+
+type catKey struct {
+	typ      TxType
+	category string
+}
+
+// categoryAcc acumula o detalhamento por tipo e categoria dos resumos
+type categoryAcc map[catKey]*CategoryTotal
+
+// add soma v ao detalhamento; linhas de rateio entram cada uma na sua categoria, convertidas
+// como a transação (a cotação já foi conferida pelo chamador)
+func (acc categoryAcc) add(m *memoryRepo, v *Transaction, base string) {
+	for _, sp := range v.categoryLines() {
+		part := *v
+		part.AmountCents = sp.AmountCents
+		amt, _ := m.convert(&part, base)
+		k := catKey{v.Type, sp.Category}
+		if acc[k] == nil {
+			acc[k] = &CategoryTotal{Type: v.Type, Category: sp.Category}
+		}
+		acc[k].TotalCents += amt
+		acc[k].Count++
+	}
+}
+
+func (acc categoryAcc) list() []CategoryTotal {
+	out := make([]CategoryTotal, 0, len(acc))
+	for _, c := range acc {
+		out = append(out, *c)
+	}
+	return out
+}
@@ -0,0 +1,132 @@
+package finance
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *memoryRepo) CreateAccount(ctx context.Context, a *Account) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *a
+	m.accounts[a.ID] = &cp
+	return nil
+}
+
+func (m *memoryRepo) GetAccount(ctx context.Context, id uuid.UUID) (*Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.accounts[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return nil, ErrNotFound
+	}
+	cp := *v
+	return &cp, nil
+}
+
+func (m *memoryRepo) ListAccounts(ctx context.Context) ([]Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	out := []Account{}
+	for _, v := range m.accounts {
+		if v.LedgerID == own {
+			out = append(out, *v)
+		}
+	}
+	slices.SortFunc(out, func(a, b Account) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out, nil
+}
+
+func (m *memoryRepo) UpdateAccount(ctx context.Context, a *Account) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.accounts[a.ID]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	if v.Currency != a.Currency && m.accountInUse(a.ID) {
+		return ErrConflict
+	}
+	cp := *a
+	m.accounts[a.ID] = &cp
+	return nil
+}
+
+func (m *memoryRepo) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.accounts[id]; !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	if m.accountInUse(id) {
+		return ErrConflict
+	}
+	delete(m.accounts, id)
+	return nil
+}
+
+// accountInUse diz se há transações, regras recorrentes ou metas na conta; exige m.mu travado
+func (m *memoryRepo) accountInUse(id uuid.UUID) bool {
+	for _, v := range m.data {
+		if v.AccountID != nil && *v.AccountID == id {
+			return true
+		}
+	}
+	for _, r := range m.recurring {
+		if r.AccountID != nil && *r.AccountID == id {
+			return true
+		}
+	}
+	for _, g := range m.goals {
+		if g.AccountID != nil && *g.AccountID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryRepo) AccountMovement(ctx context.Context, id uuid.UUID, at time.Time) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	var sum int64
+	for _, v := range m.data {
+		if v.LedgerID != own || v.AccountID == nil || *v.AccountID != id || v.OccurredAt.After(at) {
+			continue
+		}
+		switch v.Type {
+		case Income:
+			sum += v.AmountCents
+		case Expense:
+			sum -= v.AmountCents
+		case Transfer:
+			if v.Direction == TransferIn {
+				sum += v.AmountCents
+			} else {
+				sum -= v.AmountCents
+			}
+		}
+	}
+	return sum, nil
+}
+
+func (m *memoryRepo) StatementPayments(ctx context.Context, cardID uuid.UUID) ([]Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	var out []Transaction
+	for _, v := range m.data {
+		if v.LedgerID == own && v.AccountID != nil && *v.AccountID == cardID && v.Direction == TransferIn && v.Statement != "" {
+			out = append(out, *v)
+		}
+	}
+	slices.SortFunc(out, func(a, b Transaction) int { return a.OccurredAt.Compare(b.OccurredAt) })
+	return out, nil
+}
@@ -0,0 +1,68 @@
+package finance
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func copyAPIToken(t *APIToken) *APIToken {
+	cp := *t
+	cp.Scopes = slices.Clone(t.Scopes)
+	return &cp
+}
+
+func (m *memoryRepo) CreateAPIToken(ctx context.Context, t *APIToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.apiTokens[t.ID] = copyAPIToken(t)
+	return nil
+}
+
+func (m *memoryRepo) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, t := range m.apiTokens {
+		if t.Hash == hash {
+			return copyAPIToken(t), nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *memoryRepo) ListAPITokens(ctx context.Context, user uuid.UUID) ([]APIToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []APIToken{}
+	for _, t := range m.apiTokens {
+		if t.UserID == user {
+			out = append(out, *copyAPIToken(t))
+		}
+	}
+	slices.SortFunc(out, func(a, b APIToken) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return out, nil
+}
+
+func (m *memoryRepo) RevokeAPIToken(ctx context.Context, user, id uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.apiTokens[id]
+	if !ok || t.UserID != user {
+		return ErrNotFound
+	}
+	if t.RevokedAt == nil {
+		t.RevokedAt = &at
+	}
+	return nil
+}
+
+func (m *memoryRepo) TouchAPIToken(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.apiTokens[id]; ok {
+		t.LastUsedAt = &at
+	}
+	return nil
+}
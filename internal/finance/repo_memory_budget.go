@@ -0,0 +1,76 @@
+package finance
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// budgetTaken espelha o índice único (ledger_id, category_id, month) do Postgres
+func (m *memoryRepo) budgetTaken(b *Budget) bool {
+	for _, v := range m.budgets {
+		if v.ID != b.ID && v.LedgerID == b.LedgerID && v.CategoryID == b.CategoryID && v.Month == b.Month {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryRepo) CreateBudget(ctx context.Context, b *Budget) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.budgetTaken(b) {
+		return ErrConflict
+	}
+	cp := *b
+	m.budgets[b.ID] = &cp
+	return nil
+}
+
+func (m *memoryRepo) GetBudget(ctx context.Context, id uuid.UUID) (*Budget, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.budgets[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return nil, ErrNotFound
+	}
+	cp := *v
+	return &cp, nil
+}
+
+func (m *memoryRepo) ListBudgets(ctx context.Context) ([]Budget, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	out := []Budget{}
+	for _, v := range m.budgets {
+		if v.LedgerID == own {
+			out = append(out, *v)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) UpdateBudget(ctx context.Context, b *Budget) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.budgets[b.ID]; !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	if m.budgetTaken(b) {
+		return ErrConflict
+	}
+	cp := *b
+	m.budgets[b.ID] = &cp
+	return nil
+}
+
+func (m *memoryRepo) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.budgets[id]; !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	delete(m.budgets, id)
+	return nil
+}
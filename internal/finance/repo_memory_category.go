@@ -0,0 +1,126 @@
+package finance
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+func copyCategory(c *Category) *Category {
+	cp := *c
+	cp.Aliases = slices.Clone(c.Aliases)
+	return &cp
+}
+
+// nameTaken espelha o índice único (ledger_id, lower(name)) do Postgres
+func (m *memoryRepo) nameTaken(c *Category) bool {
+	for _, v := range m.categories {
+		if v.ID != c.ID && v.LedgerID == c.LedgerID && categoryKey(v.Name) == categoryKey(c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryRepo) CreateCategory(ctx context.Context, c *Category) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.nameTaken(c) {
+		return ErrConflict
+	}
+	m.categories[c.ID] = copyCategory(c)
+	return nil
+}
+
+func (m *memoryRepo) GetCategory(ctx context.Context, id uuid.UUID) (*Category, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.categories[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return nil, ErrNotFound
+	}
+	return copyCategory(v), nil
+}
+
+func (m *memoryRepo) ListCategories(ctx context.Context) ([]Category, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	out := []Category{}
+	for _, v := range m.categories {
+		if v.LedgerID == own {
+			out = append(out, *copyCategory(v))
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) UpdateCategory(ctx context.Context, c *Category, oldName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	own := ledgerScope(ctx)
+	if v, ok := m.categories[c.ID]; !ok || v.LedgerID != own {
+		return ErrNotFound
+	}
+	if m.nameTaken(c) {
+		return ErrConflict
+	}
+	m.categories[c.ID] = copyCategory(c)
+	if c.Name != oldName {
+		for _, t := range m.data {
+			if t.LedgerID != own || t.Type == Transfer {
+				continue
+			}
+			if t.Category == oldName {
+				t.Category = c.Name
+			}
+			if len(t.Splits) > 0 && t.hasCategory(oldName) {
+				t.Splits = slices.Clone(t.Splits)
+				for i := range t.Splits {
+					if t.Splits[i].Category == oldName {
+						t.Splits[i].Category = c.Name
+					}
+				}
+			}
+		}
+		for _, r := range m.recurring {
+			if r.LedgerID == own && r.Category == oldName {
+				r.Category = c.Name
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryRepo) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	own := ledgerScope(ctx)
+	c, ok := m.categories[id]
+	if !ok || c.LedgerID != own {
+		return ErrNotFound
+	}
+	for _, v := range m.categories {
+		if v.ParentID != nil && *v.ParentID == id {
+			return ErrConflict
+		}
+	}
+	for _, t := range m.data {
+		if t.LedgerID == own && t.Type != Transfer && t.hasCategory(c.Name) {
+			return ErrConflict
+		}
+	}
+	for _, r := range m.recurring {
+		if r.LedgerID == own && r.Category == c.Name {
+			return ErrConflict
+		}
+	}
+	delete(m.categories, id)
+	for k, b := range m.budgets {
+		if b.CategoryID == id {
+			delete(m.budgets, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package finance
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+func (m *memoryRepo) CreateGoal(ctx context.Context, g *Goal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *g
+	cp.Progress = nil
+	m.goals[g.ID] = &cp
+	return nil
+}
+
+func (m *memoryRepo) GetGoal(ctx context.Context, id uuid.UUID) (*Goal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.goals[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return nil, ErrNotFound
+	}
+	cp := *v
+	return &cp, nil
+}
+
+func (m *memoryRepo) ListGoals(ctx context.Context) ([]Goal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	out := []Goal{}
+	for _, v := range m.goals {
+		if v.LedgerID == own {
+			out = append(out, *v)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) DeleteGoal(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.goals[id]; !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	delete(m.goals, id)
+	return nil
+}
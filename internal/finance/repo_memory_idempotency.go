@@ -0,0 +1,52 @@
+package finance
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// idempotencyKey isola as chaves por livro: livros diferentes podem repetir o mesmo valor
+type idempotencyKey struct {
+	ledger uuid.UUID
+	key    string
+}
+
+func (m *memoryRepo) GetIdempotencyKey(ctx context.Context, key string, now time.Time) (*IdempotencyRecord, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rec, ok := m.idempotency[idempotencyKey{ledgerScope(ctx), key}]
+	if !ok || !rec.ExpiresAt.After(now) {
+		return nil, ErrNotFound
+	}
+	cp := *rec
+	return &cp, nil
+}
+
+func (m *memoryRepo) CreateIdempotent(ctx context.Context, t *Transaction, rec *IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := idempotencyKey{ledgerScope(ctx), rec.Key}
+	if old, ok := m.idempotency[k]; ok && old.ExpiresAt.After(rec.CreatedAt) {
+		return ErrConflict
+	}
+	cp := *t
+	m.data[t.ID] = &cp
+	rc := *rec
+	m.idempotency[k] = &rc
+	return nil
+}
+
+func (m *memoryRepo) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for k, rec := range m.idempotency {
+		if !rec.ExpiresAt.After(now) {
+			delete(m.idempotency, k)
+			n++
+		}
+	}
+	return n, nil
+}
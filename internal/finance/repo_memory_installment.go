@@ -0,0 +1,96 @@
+package finance
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+func (m *memoryRepo) CreateInstallment(ctx context.Context, p *Installment, txs []*Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *p
+	cp.Transactions = nil
+	m.purchases[p.ID] = &cp
+	for _, t := range txs {
+		c := *t
+		m.data[t.ID] = &c
+	}
+	return nil
+}
+
+func (m *memoryRepo) GetInstallment(ctx context.Context, id uuid.UUID) (*Installment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.purchases[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return nil, ErrNotFound
+	}
+	cp := *v
+	return &cp, nil
+}
+
+func (m *memoryRepo) ListInstallments(ctx context.Context) ([]Installment, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	out := []Installment{}
+	for _, v := range m.purchases {
+		if v.LedgerID == own {
+			out = append(out, *v)
+		}
+	}
+	slices.SortFunc(out, func(a, b Installment) int { return b.FirstAt.Compare(a.FirstAt) })
+	return out, nil
+}
+
+func (m *memoryRepo) InstallmentTxs(ctx context.Context, id uuid.UUID) ([]Transaction, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	out := []Transaction{}
+	for _, v := range m.data {
+		if v.LedgerID == own && v.InstallmentID != nil && *v.InstallmentID == id {
+			out = append(out, *v)
+		}
+	}
+	slices.SortFunc(out, func(a, b Transaction) int { return a.InstallmentNumber - b.InstallmentNumber })
+	return out, nil
+}
+
+func (m *memoryRepo) SettleInstallment(ctx context.Context, p *Installment, remove []uuid.UUID, add *Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.purchases[p.ID]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	if v.Status != InstallmentActive {
+		return ErrConflict
+	}
+	v.Status, v.UpdatedAt = p.Status, p.UpdatedAt
+	for _, id := range remove {
+		delete(m.data, id)
+	}
+	if add != nil {
+		cp := *add
+		m.data[add.ID] = &cp
+	}
+	return nil
+}
+
+func (m *memoryRepo) DeleteInstallment(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.purchases[id]; !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	for k, v := range m.data {
+		if v.InstallmentID != nil && *v.InstallmentID == id {
+			delete(m.data, k)
+		}
+	}
+	delete(m.purchases, id)
+	return nil
+}
@@ -0,0 +1,106 @@
+package finance
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type memberKey struct{ ledger, user uuid.UUID }
+
+func (m *memoryRepo) CreateLedger(ctx context.Context, l *Ledger, owner uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *l
+	cp.Role = ""
+	m.ledgers[l.ID] = &cp
+	m.members[memberKey{l.ID, owner}] = &LedgerMember{LedgerID: l.ID, UserID: owner, Role: RoleOwner, CreatedAt: l.CreatedAt}
+	return nil
+}
+
+func (m *memoryRepo) ListLedgers(ctx context.Context, user uuid.UUID) ([]Ledger, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []Ledger{}
+	for k, mem := range m.members {
+		if k.user != user {
+			continue
+		}
+		if l, ok := m.ledgers[k.ledger]; ok {
+			cp := *l
+			cp.Role = mem.Role
+			out = append(out, cp)
+		}
+	}
+	slices.SortFunc(out, func(a, b Ledger) int { return strings.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+func (m *memoryRepo) GetMember(ctx context.Context, ledger, user uuid.UUID) (*LedgerMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.members[memberKey{ledger, user}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *v
+	if u, ok := m.users[user]; ok {
+		cp.Email = u.Email
+	}
+	return &cp, nil
+}
+
+func (m *memoryRepo) ListMembers(ctx context.Context, ledger uuid.UUID) ([]LedgerMember, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []LedgerMember{}
+	for k, v := range m.members {
+		if k.ledger != ledger {
+			continue
+		}
+		cp := *v
+		if u, ok := m.users[k.user]; ok {
+			cp.Email = u.Email
+		}
+		out = append(out, cp)
+	}
+	slices.SortFunc(out, func(a, b LedgerMember) int { return strings.Compare(a.Email, b.Email) })
+	return out, nil
+}
+
+func (m *memoryRepo) AddMember(ctx context.Context, mem *LedgerMember) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := memberKey{mem.LedgerID, mem.UserID}
+	if _, ok := m.members[k]; ok {
+		return ErrConflict
+	}
+	cp := *mem
+	m.members[k] = &cp
+	return nil
+}
+
+func (m *memoryRepo) UpdateMember(ctx context.Context, mem *LedgerMember) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := memberKey{mem.LedgerID, mem.UserID}
+	if _, ok := m.members[k]; !ok {
+		return ErrNotFound
+	}
+	cp := *mem
+	m.members[k] = &cp
+	return nil
+}
+
+func (m *memoryRepo) RemoveMember(ctx context.Context, ledger, user uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := memberKey{ledger, user}
+	if _, ok := m.members[k]; !ok {
+		return ErrNotFound
+	}
+	delete(m.members, k)
+	return nil
+}
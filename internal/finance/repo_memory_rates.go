@@ -0,0 +1,59 @@
+package finance
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+type ratePair struct{ from, to string }
+
+func (m *memoryRepo) UpsertRates(ctx context.Context, rates []ExchangeRate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	lid := ledgerScope(ctx)
+	book := m.rates[lid]
+	if book == nil {
+		book = rateBook{}
+		m.rates[lid] = book
+	}
+	for _, r := range rates {
+		k := ratePair{r.From, r.To}
+		list := book[k]
+		i, found := slices.BinarySearchFunc(list, r.Date, func(e ExchangeRate, d time.Time) int {
+			return e.Date.Compare(d)
+		})
+		if found {
+			list[i] = r
+		} else {
+			list = slices.Insert(list, i, r)
+		}
+		book[k] = list
+	}
+	return nil
+}
+
+func (m *memoryRepo) ListRates(ctx context.Context, from, to string) ([]ExchangeRate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []ExchangeRate{}
+	for k, list := range m.rates[ledgerScope(ctx)] {
+		if (from == "" || k.from == from) && (to == "" || k.to == to) {
+			out = append(out, list...)
+		}
+	}
+	slices.SortFunc(out, func(a, b ExchangeRate) int {
+		if c := strings.Compare(a.From+a.To, b.From+b.To); c != 0 {
+			return c
+		}
+		return a.Date.Compare(b.Date)
+	})
+	return out, nil
+}
+
+// convert leva o valor da transação para a moeda base usando a cotação do livro dela
+// vigente em OccurredAt; exige m.mu travado
+func (m *memoryRepo) convert(t *Transaction, base string) (int64, bool) {
+	return m.rates[t.LedgerID].convert(t.AmountCents, t.Currency, base, t.OccurredAt)
+}
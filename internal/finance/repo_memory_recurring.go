@@ -0,0 +1,104 @@
+package finance
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func copyRecurring(r *RecurringRule) *RecurringRule {
+	cp := *r
+	cp.Skipped = slices.Clone(r.Skipped)
+	return &cp
+}
+
+func (m *memoryRepo) CreateRecurring(ctx context.Context, r *RecurringRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recurring[r.ID] = copyRecurring(r)
+	return nil
+}
+
+func (m *memoryRepo) GetRecurring(ctx context.Context, id uuid.UUID) (*RecurringRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.recurring[id]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return nil, ErrNotFound
+	}
+	return copyRecurring(v), nil
+}
+
+func (m *memoryRepo) ListRecurring(ctx context.Context) ([]RecurringRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	out := []RecurringRule{}
+	for _, v := range m.recurring {
+		if v.LedgerID == own {
+			out = append(out, *copyRecurring(v))
+		}
+	}
+	slices.SortFunc(out, func(a, b RecurringRule) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return out, nil
+}
+
+func (m *memoryRepo) UpdateRecurring(ctx context.Context, r *RecurringRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.recurring[r.ID]
+	if !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	v.Skipped = slices.Clone(r.Skipped)
+	v.UpdatedAt = r.UpdatedAt
+	return nil
+}
+
+func (m *memoryRepo) DeleteRecurring(ctx context.Context, id uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.recurring[id]; !ok || v.LedgerID != ledgerScope(ctx) {
+		return ErrNotFound
+	}
+	delete(m.recurring, id)
+	return nil
+}
+
+func (m *memoryRepo) DueRecurring(ctx context.Context, at time.Time) ([]RecurringRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := []RecurringRule{}
+	for _, v := range m.recurring {
+		if v.NextAt != nil && !v.NextAt.After(at) {
+			out = append(out, *copyRecurring(v))
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryRepo) AdvanceRecurring(ctx context.Context, r *RecurringRule, prevDone int, tx *Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.recurring[r.ID]
+	if !ok || v.Done != prevDone {
+		return ErrConflict
+	}
+	v.Done, v.NextAt, v.UpdatedAt = r.Done, r.NextAt, r.UpdatedAt
+	if tx != nil {
+		cp := *tx
+		m.data[tx.ID] = &cp
+	}
+	return nil
+}
+
+// RunExclusive usa um único lock por processo, suficiente para uma instância em memória
+func (m *memoryRepo) RunExclusive(ctx context.Context, name string, fn func(context.Context) error) (bool, error) {
+	if !m.exclusive.TryLock() {
+		return false, nil
+	}
+	defer m.exclusive.Unlock()
+	return true, fn(ctx)
+}
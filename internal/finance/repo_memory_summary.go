@@ -0,0 +1,43 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (m *memoryRepo) RangeSummary(ctx context.Context, from, to time.Time, g Granularity, f TxFilter, base string) (*RangeSummary, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	byStart := map[time.Time]*SummaryBucket{}
+	byCat := categoryAcc{}
+	for _, v := range m.data {
+		if v.LedgerID != own || v.Type == Transfer || v.OccurredAt.Before(from) || v.OccurredAt.After(to) || !f.matches(v) {
+			continue
+		}
+		v := f.summaryPart(v)
+		amt, ok := m.convert(v, base)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s to %s on %s", ErrMissingRate, v.Currency, base, v.OccurredAt.Format("2006-01-02"))
+		}
+		start := g.truncate(v.OccurredAt)
+		b := byStart[start]
+		if b == nil {
+			b = &SummaryBucket{Start: start}
+			byStart[start] = b
+		}
+		if v.Type == Income {
+			b.Income += amt
+		} else {
+			b.Expense += amt
+		}
+		b.CountTx++
+		byCat.add(m, v, base)
+	}
+	rs := &RangeSummary{Categories: byCat.list()}
+	for _, b := range byStart {
+		rs.Buckets = append(rs.Buckets, *b)
+	}
+	return rs, nil
+}
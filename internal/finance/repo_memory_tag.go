@@ -0,0 +1,42 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func (m *memoryRepo) TagTotals(ctx context.Context, from, to time.Time, f TxFilter, base string) ([]TagTotal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	own := ledgerScope(ctx)
+	byTag := map[string]*TagTotal{}
+	for _, v := range m.data {
+		if v.LedgerID != own || v.Type == Transfer || len(v.Tags) == 0 || v.OccurredAt.Before(from) || v.OccurredAt.After(to) || !f.matches(v) {
+			continue
+		}
+		v := f.summaryPart(v)
+		amt, ok := m.convert(v, base)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s to %s on %s", ErrMissingRate, v.Currency, base, v.OccurredAt.Format("2006-01-02"))
+		}
+		for _, tag := range v.Tags {
+			tt := byTag[tag]
+			if tt == nil {
+				tt = &TagTotal{Tag: tag}
+				byTag[tag] = tt
+			}
+			if v.Type == Income {
+				tt.IncomeCents += amt
+			} else {
+				tt.ExpenseCents += amt
+			}
+			tt.Count++
+		}
+	}
+	out := make([]TagTotal, 0, len(byTag))
+	for _, tt := range byTag {
+		out = append(out, *tt)
+	}
+	return out, nil
+}
@@ -2,19 +2,23 @@ package finance
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 func TestMemoryRepo_BasicFlow(t *testing.T) {
 	r := NewMemoryRepo()
-	s := NewService(r)
+	s := NewService(r, WithCategoryAutoCreate(true))
 
-	_, err := s.Create(context.Background(), Income, "salary", 500000, "salário")
+	_, err := s.Create(context.Background(), TxInput{Type: Income, Category: "salary", AmountCents: 500000, Description: "salário"})
 	if err != nil {
 		t.Fatalf("create income: %v", err)
 	}
-	_, err = s.Create(context.Background(), Expense, "rent", 150000, "aluguel")
+	_, err = s.Create(context.Background(), TxInput{Type: Expense, Category: "rent", AmountCents: 150000, Description: "aluguel"})
 	if err != nil {
 		t.Fatalf("create expense: %v", err)
 	}
@@ -22,16 +26,169 @@ func TestMemoryRepo_BasicFlow(t *testing.T) {
 	now := time.Now()
 	from := now.Add(-24 * time.Hour)
 	to := now.Add(24 * time.Hour)
-	list, err := s.ListByPeriod(context.Background(), from, to)
+	list, err := s.ListByPeriod(context.Background(), from, to, TxFilter{})
 	if err != nil || len(list) != 2 {
 		t.Fatalf("list: err=%v len=%d", err, len(list))
 	}
 
-	sum, err := s.MonthlySummary(context.Background(), now.Year(), int(now.Month()))
+	sum, err := s.MonthlySummary(context.Background(), now.Year(), int(now.Month()), TxFilter{}, "")
 	if err != nil {
 		t.Fatalf("summary: %v", err)
 	}
 	if sum.Income != 500000 || sum.Expense != 150000 || sum.Net != 350000 {
 		t.Fatalf("summary mismatch: %+v", sum)
 	}
-}
\ No newline at end of file
+}
+
+func TestMonthlySummary_CategoryBreakdown(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	at := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	for _, in := range []TxInput{
+		{Type: Income, Category: "salary", AmountCents: 500000},
+		{Type: Expense, Category: "Moradia > Aluguel", AmountCents: 150000},
+		{Type: Expense, Category: "food", AmountCents: 30000},
+		{Type: Expense, Category: "food", AmountCents: 20000},
+	} {
+		in.OccurredAt = at
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sum, err := s.MonthlySummary(ctx, 2025, 3, TxFilter{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CategoryTotal{
+		{Type: Income, Category: "salary", Path: "salary", TotalCents: 500000, Count: 1, Percent: 100},
+		{Type: Expense, Category: "Aluguel", Path: "Moradia > Aluguel", TotalCents: 150000, Count: 1, Percent: 75},
+		{Type: Expense, Category: "food", Path: "food", TotalCents: 50000, Count: 2, Percent: 25},
+	}
+	if len(sum.Categories) != len(want) {
+		t.Fatalf("categories: %+v", sum.Categories)
+	}
+	for i, c := range sum.Categories {
+		if c != want[i] {
+			t.Errorf("categories[%d] = %+v, want %+v", i, c, want[i])
+		}
+	}
+	report, err := s.GenerateMonthlyReport(ctx, 2025, 3, "")
+	if err != nil || !strings.Contains(report, "DESPESAS POR CATEGORIA") || !strings.Contains(report, " 75.0%") {
+		t.Errorf("report without category table (%v):\n%s", err, report)
+	}
+}
+
+func TestMemoryRepo_Update(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+
+	tx, err := s.Create(ctx, TxInput{Type: Expense, Category: "fod", AmountCents: 2500, Description: "mercado"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	cat := " food "
+	amount := int64(2700)
+	upd, err := s.Update(ctx, tx.ID, TxPatch{Category: &cat, AmountCents: &amount})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if upd.ID != tx.ID || upd.Category != "food" || upd.AmountCents != 2700 || upd.Description != "mercado" {
+		t.Fatalf("update mismatch: %+v", upd)
+	}
+	if upd.UpdatedAt.Before(tx.UpdatedAt) {
+		t.Fatalf("updated_at not bumped: %v < %v", upd.UpdatedAt, tx.UpdatedAt)
+	}
+
+	got, err := s.Get(ctx, tx.ID)
+	if err != nil || got.Category != "food" {
+		t.Fatalf("get: err=%v tx=%+v", err, got)
+	}
+
+	zero := int64(0)
+	if _, err := s.Update(ctx, tx.ID, TxPatch{AmountCents: &zero}); !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+	if _, err := s.Get(ctx, uuid.New()); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestService_CreateOccurredAt(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithMaxFuture(time.Hour), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+
+	past := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	if _, err := s.Create(ctx, TxInput{Type: Expense, Category: "food", AmountCents: 1000, OccurredAt: past}); err != nil {
+		t.Fatalf("create past: %v", err)
+	}
+	sum, err := s.MonthlySummary(ctx, 2024, 3, TxFilter{}, "")
+	if err != nil || sum.Expense != 1000 || sum.CountTx != 1 {
+		t.Fatalf("summary: err=%v sum=%+v", err, sum)
+	}
+
+	future := time.Now().Add(48 * time.Hour)
+	if _, err := s.Create(ctx, TxInput{Type: Expense, Category: "food", AmountCents: 1000, OccurredAt: future}); !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected ErrBadRequest for far future, got %v", err)
+	}
+}
+
+func TestService_CreateBatch(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	day := time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)
+	ins := []TxInput{
+		{Type: Income, Category: "salary", AmountCents: 1000, OccurredAt: day},
+		{Type: Expense, Category: "", AmountCents: 500, OccurredAt: day},
+		{Type: Expense, Category: "food", AmountCents: 300, OccurredAt: day},
+	}
+
+	res, err := s.CreateBatch(ctx, ins, true)
+	if err != nil || res.Created != 0 || res.Failed != 1 || res.Results[1].Error == "" {
+		t.Fatalf("atomic batch: err=%v res=%+v", err, res)
+	}
+	if sum, _ := s.MonthlySummary(ctx, 2025, 8, TxFilter{}, ""); sum.CountTx != 0 {
+		t.Fatalf("atomic batch persisted items: %+v", sum)
+	}
+
+	res, err = s.CreateBatch(ctx, ins, false)
+	if err != nil || res.Created != 2 || res.Failed != 1 {
+		t.Fatalf("non-atomic batch: err=%v res=%+v", err, res)
+	}
+	if sum, _ := s.MonthlySummary(ctx, 2025, 8, TxFilter{}, ""); sum.CountTx != 2 || sum.Net != 700 {
+		t.Fatalf("non-atomic summary: %+v", sum)
+	}
+}
+
+func TestService_CreateIdempotent(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithIdempotencyTTL(time.Hour), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	in := TxInput{Type: Expense, Category: "food", AmountCents: 1500}
+
+	first, replayed, err := s.CreateIdempotent(ctx, "k1", "h1", in)
+	if err != nil || replayed {
+		t.Fatalf("first call: replayed=%v err=%v", replayed, err)
+	}
+	again, replayed, err := s.CreateIdempotent(ctx, "k1", "h1", in)
+	if err != nil || !replayed {
+		t.Fatalf("retry: replayed=%v err=%v", replayed, err)
+	}
+	if string(again.Response) != string(first.Response) {
+		t.Errorf("replayed response differs")
+	}
+	if _, _, err := s.CreateIdempotent(ctx, "k1", "h2", in); !errors.Is(err, ErrIdempotencyMismatch) {
+		t.Errorf("expected ErrIdempotencyMismatch, got %v", err)
+	}
+	list, _ := s.ListByPeriod(ctx, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), TxFilter{})
+	if len(list) != 1 {
+		t.Errorf("want 1 transaction, got %d", len(list))
+	}
+
+	// chave expirada volta a ficar livre
+	if n, err := s.repo.PurgeIdempotencyKeys(ctx, time.Now().Add(2*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("purge: n=%d err=%v", n, err)
+	}
+	if _, replayed, err := s.CreateIdempotent(ctx, "k1", "h2", in); err != nil || replayed {
+		t.Errorf("after expiry: replayed=%v err=%v", replayed, err)
+	}
+}
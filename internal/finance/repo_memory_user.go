@@ -0,0 +1,45 @@
+package finance
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+func (m *memoryRepo) CreateUser(ctx context.Context, u *User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, v := range m.users {
+		if v.Email == u.Email {
+			return ErrConflict
+		}
+	}
+	cp := *u
+	m.users[u.ID] = &cp
+	m.ledgers[u.ID] = &Ledger{ID: u.ID, Name: PersonalLedgerName, CreatedAt: u.CreatedAt}
+	m.members[memberKey{u.ID, u.ID}] = &LedgerMember{LedgerID: u.ID, UserID: u.ID, Role: RoleOwner, CreatedAt: u.CreatedAt}
+	return nil
+}
+
+func (m *memoryRepo) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *v
+	return &cp, nil
+}
+
+func (m *memoryRepo) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, v := range m.users {
+		if v.Email == email {
+			cp := *v
+			return &cp, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -3,6 +3,11 @@ package finance
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,33 +19,310 @@ type pgRepo struct {
 
 func NewPostgresRepo(db *sql.DB) Repository { return &pgRepo{db: db} }
 
+// txColumns é a lista de colunas lida por scanTx, na mesma ordem; tags e linhas de rateio
+// vêm como arrays JSON de transaction_tags e transaction_splits
+const txColumns = `id, ledger_id, type, category, amount_cents, currency, occurred_at, description, account_id,
+	transfer_id, COALESCE(direction, ''), COALESCE(external_id, ''), installment_id, COALESCE(installment_number, 0),
+	COALESCE(statement, ''), created_at, updated_at,
+	COALESCE((SELECT json_agg(tt.tag ORDER BY tt.tag) FROM transaction_tags tt WHERE tt.transaction_id = transactions.id), '[]'),
+	COALESCE((SELECT json_agg(json_build_object('category', sp.category, 'amount_cents', sp.amount_cents, 'note', sp.note)
+		ORDER BY sp.line) FROM transaction_splits sp WHERE sp.transaction_id = transactions.id), '[]')`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// execer é satisfeito tanto por *sql.DB quanto por *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func scanTx(sc rowScanner) (Transaction, error) {
+	var t Transaction
+	var tags, splits []byte
+	err := sc.Scan(&t.ID, &t.LedgerID, &t.Type, &t.Category, &t.AmountCents, &t.Currency, &t.OccurredAt, &t.Description, &t.AccountID,
+		&t.TransferID, &t.Direction, &t.ExternalID, &t.InstallmentID, &t.InstallmentNumber, &t.Statement,
+		&t.CreatedAt, &t.UpdatedAt, &tags, &splits)
+	if err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(tags, &t.Tags); err != nil {
+		return t, err
+	}
+	if err := json.Unmarshal(splits, &t.Splits); err != nil {
+		return t, err
+	}
+	if len(t.Tags) == 0 {
+		t.Tags = nil
+	}
+	if len(t.Splits) == 0 {
+		t.Splits = nil
+	}
+	return t, nil
+}
+
+const txInsert = `
+	INSERT INTO transactions (id, ledger_id, type, category, amount_cents, currency, occurred_at, description, account_id,
+		transfer_id, direction, external_id, installment_id, installment_number, statement, created_at, updated_at)
+	VALUES `
+
+// txValues adiciona os parâmetros da transação e devolve a tupla do VALUES
+func txValues(args *sqlArgs, t *Transaction) string {
+	return "(" + strings.Join([]string{
+		args.add(t.ID), args.add(t.LedgerID), args.add(t.Type), args.add(t.Category), args.add(t.AmountCents), args.add(t.Currency),
+		args.add(t.OccurredAt), args.add(t.Description), args.add(t.AccountID), args.add(t.TransferID),
+		"NULLIF(" + args.add(t.Direction) + ",'')",
+		"NULLIF(" + args.add(t.ExternalID) + ",'')", args.add(t.InstallmentID),
+		"NULLIF(" + args.add(t.InstallmentNumber) + ",0)", "NULLIF(" + args.add(t.Statement) + ",'')",
+		args.add(t.CreatedAt), args.add(t.UpdatedAt),
+	}, ",") + ")"
+}
+
+// insertTx grava a transação, as tags e o rateio; ex deve ser uma transação do banco quando
+// houver tags ou rateio (ver hasChildren)
+func insertTx(ctx context.Context, ex execer, t *Transaction) error {
+	var args sqlArgs
+	if _, err := ex.ExecContext(ctx, txInsert+txValues(&args, t), args...); err != nil {
+		return err
+	}
+	return insertChildren(ctx, ex, t)
+}
+
+func hasChildren(t *Transaction) bool { return len(t.Tags) > 0 || len(t.Splits) > 0 }
+
+// insertChildren grava as tags e as linhas de rateio das transações
+func insertChildren(ctx context.Context, ex execer, ts ...*Transaction) error {
+	if err := insertTags(ctx, ex, ts...); err != nil {
+		return err
+	}
+	for _, t := range ts {
+		if len(t.Splits) == 0 {
+			continue
+		}
+		lines, _ := json.Marshal(t.Splits)
+		const q = `
+			INSERT INTO transaction_splits (transaction_id, line, category, amount_cents, note)
+			SELECT $1, e.line, e.x->>'category', (e.x->>'amount_cents')::bigint, COALESCE(e.x->>'note', '')
+			FROM jsonb_array_elements($2::jsonb) WITH ORDINALITY AS e(x, line)
+		`
+		if _, err := ex.ExecContext(ctx, q, t.ID, string(lines)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertTags cria as tags que faltam no livro e vincula as transações a elas
+func insertTags(ctx context.Context, ex execer, ts ...*Transaction) error {
+	for _, t := range ts {
+		if len(t.Tags) == 0 {
+			continue
+		}
+		tags := string(jsonStrings(t.Tags))
+		_, err := ex.ExecContext(ctx,
+			`INSERT INTO tags (ledger_id, name) SELECT $1, jsonb_array_elements_text($2::jsonb) ON CONFLICT DO NOTHING`,
+			t.LedgerID, tags)
+		if err != nil {
+			return err
+		}
+		_, err = ex.ExecContext(ctx,
+			`INSERT INTO transaction_tags (transaction_id, ledger_id, tag) SELECT $1, $2, jsonb_array_elements_text($3::jsonb)`,
+			t.ID, t.LedgerID, tags)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchInsertRows limita as linhas por INSERT para ficar abaixo do teto de parâmetros do Postgres
+const batchInsertRows = 500
+
+// insertTxs usa INSERT multi-linha em blocos de batchInsertRows
+func insertTxs(ctx context.Context, ex execer, ts []*Transaction) error {
+	for start := 0; start < len(ts); start += batchInsertRows {
+		end := min(start+batchInsertRows, len(ts))
+		var args sqlArgs
+		tuples := make([]string, 0, end-start)
+		for _, t := range ts[start:end] {
+			tuples = append(tuples, txValues(&args, t))
+		}
+		if _, err := ex.ExecContext(ctx, txInsert+strings.Join(tuples, ","), args...); err != nil {
+			return err
+		}
+	}
+	return insertChildren(ctx, ex, ts...)
+}
+
 func (p *pgRepo) Create(ctx context.Context, t *Transaction) error {
+	if !hasChildren(t) {
+		return insertTx(ctx, p.db, t)
+	}
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	if err := insertTx(ctx, dbtx, t); err != nil {
+		return err
+	}
+	return dbtx.Commit()
+}
+
+// CreateExternal conta com o índice único de migrations/005_external_ids.sql
+func (p *pgRepo) CreateExternal(ctx context.Context, t *Transaction) (bool, error) {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer dbtx.Rollback()
+	var args sqlArgs
+	res, err := dbtx.ExecContext(ctx, txInsert+txValues(&args, t)+" ON CONFLICT DO NOTHING", args...)
+	if err != nil {
+		return false, err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return false, nil
+	}
+	if err := insertChildren(ctx, dbtx, t); err != nil {
+		return false, err
+	}
+	return true, dbtx.Commit()
+}
+
+func (p *pgRepo) CreateBatch(ctx context.Context, ts []*Transaction) error {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	if err := insertTxs(ctx, dbtx, ts); err != nil {
+		return err
+	}
+	return dbtx.Commit()
+}
+
+// CreateTransfer grava as duas pernas da transferência em uma única transação do banco
+func (p *pgRepo) CreateTransfer(ctx context.Context, out, in *Transaction) error {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	if err := insertTx(ctx, dbtx, out); err != nil {
+		return err
+	}
+	if err := insertTx(ctx, dbtx, in); err != nil {
+		return err
+	}
+	return dbtx.Commit()
+}
+
+func (p *pgRepo) Get(ctx context.Context, id uuid.UUID) (*Transaction, error) {
+	q := `SELECT ` + txColumns + ` FROM transactions WHERE id = $1 AND ledger_id = $2`
+	t, err := scanTx(p.db.QueryRowContext(ctx, q, id, ledgerScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (p *pgRepo) Update(ctx context.Context, t *Transaction) error {
 	const q = `
-		INSERT INTO transactions (id, type, category, amount_cents, occurred_at, description, created_at, updated_at)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+		UPDATE transactions
+		SET type = $2, category = $3, amount_cents = $4, currency = $5, occurred_at = $6, description = $7,
+			account_id = $8, updated_at = $9
+		WHERE id = $1 AND ledger_id = $10
 	`
-	_, err := p.db.ExecContext(ctx, q,
-		t.ID, t.Type, t.Category, t.AmountCents, t.OccurredAt, t.Description, t.CreatedAt, t.UpdatedAt,
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	res, err := dbtx.ExecContext(ctx, q,
+		t.ID, t.Type, t.Category, t.AmountCents, t.Currency, t.OccurredAt, t.Description, t.AccountID, t.UpdatedAt,
+		ledgerScope(ctx),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return ErrNotFound
+	}
+	// tags e rateio são regravados por inteiro
+	if _, err := dbtx.ExecContext(ctx, `DELETE FROM transaction_tags WHERE transaction_id = $1`, t.ID); err != nil {
+		return err
+	}
+	if _, err := dbtx.ExecContext(ctx, `DELETE FROM transaction_splits WHERE transaction_id = $1`, t.ID); err != nil {
+		return err
+	}
+	if err := insertChildren(ctx, dbtx, t); err != nil {
+		return err
+	}
+	return dbtx.Commit()
 }
 
-func (p *pgRepo) ListByPeriod(ctx context.Context, from, to time.Time) ([]Transaction, error) {
-	const q = `
-		SELECT id, type, category, amount_cents, occurred_at, description, created_at, updated_at
-		FROM transactions
-		WHERE occurred_at >= $1 AND occurred_at <= $2
-		ORDER BY occurred_at ASC, created_at ASC
-	`
-	rows, err := p.db.QueryContext(ctx, q, from, to)
+// sqlArgs acumula parâmetros posicionais ($1, $2, ...) para consultas montadas dinamicamente
+type sqlArgs []any
+
+func (a *sqlArgs) add(v any) string {
+	*a = append(*a, v)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// likeEscape escapa os curingas do LIKE para busca literal por substring
+var likeEscape = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// filterConds traduz o TxFilter em condições SQL (unidas por AND), sempre restritas ao
+// livro do contexto
+func filterConds(ctx context.Context, f TxFilter, args *sqlArgs) []string {
+	conds := []string{"ledger_id = " + args.add(ledgerScope(ctx))}
+	if f.AccountID != nil {
+		conds = append(conds, "account_id = "+args.add(*f.AccountID))
+	}
+	if f.Type != "" {
+		conds = append(conds, "type = "+args.add(f.Type))
+	}
+	if f.Category != "" {
+		c := args.add(f.Category)
+		conds = append(conds, "(category = "+c+" OR EXISTS (SELECT 1 FROM transaction_splits sp"+
+			" WHERE sp.transaction_id = transactions.id AND sp.category = "+c+"))")
+	}
+	if f.MinAmount != nil {
+		conds = append(conds, "amount_cents >= "+args.add(*f.MinAmount))
+	}
+	if f.MaxAmount != nil {
+		conds = append(conds, "amount_cents <= "+args.add(*f.MaxAmount))
+	}
+	if f.Description != "" {
+		conds = append(conds, "description ILIKE '%' || "+args.add(likeEscape.Replace(f.Description))+" || '%'")
+	}
+	if len(f.Tags) > 0 {
+		tagged := "FROM transaction_tags tt WHERE tt.transaction_id = transactions.id" +
+			" AND tt.tag IN (SELECT jsonb_array_elements_text(" + args.add(string(jsonStrings(f.Tags))) + "::jsonb))"
+		if f.TagMatch == TagsAll {
+			conds = append(conds, "(SELECT COUNT(*) "+tagged+") = "+args.add(len(f.Tags)))
+		} else {
+			conds = append(conds, "EXISTS (SELECT 1 "+tagged+")")
+		}
+	}
+	return conds
+}
+
+func (p *pgRepo) queryTxs(ctx context.Context, q string, args ...any) ([]Transaction, error) {
+	rows, err := p.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []Transaction
 	for rows.Next() {
-		var t Transaction
-		if err := rows.Scan(&t.ID, &t.Type, &t.Category, &t.AmountCents, &t.OccurredAt, &t.Description, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		t, err := scanTx(rows)
+		if err != nil {
 			return nil, err
 		}
 		out = append(out, t)
@@ -48,8 +330,66 @@ func (p *pgRepo) ListByPeriod(ctx context.Context, from, to time.Time) ([]Transa
 	return out, rows.Err()
 }
 
+func (p *pgRepo) ListByPeriod(ctx context.Context, from, to time.Time, f TxFilter) ([]Transaction, error) {
+	var args sqlArgs
+	conds := append([]string{
+		"occurred_at >= " + args.add(from),
+		"occurred_at <= " + args.add(to),
+	}, filterConds(ctx, f, &args)...)
+	q := `SELECT ` + txColumns + ` FROM transactions WHERE ` + strings.Join(conds, " AND ") +
+		` ORDER BY occurred_at ASC, created_at ASC`
+	return p.queryTxs(ctx, q, args...)
+}
+
+// sortColumns mapeia TxSort para a coluna do ORDER BY (desempate sempre por id)
+var sortColumns = map[TxSort]string{
+	SortOccurredAt: "occurred_at",
+	SortAmount:     "amount_cents",
+	SortCategory:   "category",
+}
+
+// List pagina por keyset: (coluna, id) > (valor do cursor, id do cursor)
+func (p *pgRepo) List(ctx context.Context, q TxQuery) ([]Transaction, error) {
+	col := sortColumns[q.Sort]
+	dir, op := "ASC", ">"
+	if q.Desc {
+		dir, op = "DESC", "<"
+	}
+	var args sqlArgs
+	conds := append([]string{
+		"occurred_at >= " + args.add(q.From),
+		"occurred_at <= " + args.add(q.To),
+	}, filterConds(ctx, q.Filter, &args)...)
+	if q.After != nil {
+		after, err := cursorTx(q.After)
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		switch q.Sort {
+		case SortAmount:
+			v = after.AmountCents
+		case SortCategory:
+			v = after.Category
+		default:
+			v = after.OccurredAt
+		}
+		conds = append(conds, fmt.Sprintf("(%s, id) %s (%s, %s)", col, op, args.add(v), args.add(after.ID)))
+	}
+	sqlq := fmt.Sprintf(`SELECT %s FROM transactions WHERE %s ORDER BY %s %s, id %s LIMIT %s`,
+		txColumns, strings.Join(conds, " AND "), col, dir, dir, args.add(q.Limit))
+	return p.queryTxs(ctx, sqlq, args...)
+}
+
+// Delete remove a transação; se for perna de transferência, remove as duas
 func (p *pgRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	res, err := p.db.ExecContext(ctx, `DELETE FROM transactions WHERE id = $1`, id)
+	const q = `
+		DELETE FROM transactions
+		WHERE ledger_id = $2 AND (
+			id = $1 OR transfer_id = (SELECT transfer_id FROM transactions WHERE id = $1 AND ledger_id = $2)
+		)
+	`
+	res, err := p.db.ExecContext(ctx, q, id, ledgerScope(ctx))
 	if err != nil {
 		return err
 	}
@@ -60,34 +400,48 @@ func (p *pgRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (p *pgRepo) MonthlySummary(ctx context.Context, year int, month int) (*MonthlySummary, error) {
-	const q = `
-		WITH m AS (
-			SELECT
-				SUM(CASE WHEN type = 'income'  THEN amount_cents ELSE 0 END) AS income,
-				SUM(CASE WHEN type = 'expense' THEN amount_cents ELSE 0 END) AS expense,
-				COUNT(*) AS cnt,
-				MIN(occurred_at) AS first_tx,
-				MAX(occurred_at) AS last_tx
+// MonthlySummary converte cada valor com fx_convert (ver migrations/018_ledger_rates.sql)
+func (p *pgRepo) MonthlySummary(ctx context.Context, year int, month int, f TxFilter, base string) (*MonthlySummary, error) {
+	args := sqlArgs{base}
+	conds := append([]string{
+		"type <> 'transfer'",
+		"EXTRACT(YEAR FROM occurred_at) = " + args.add(year),
+		"EXTRACT(MONTH FROM occurred_at) = " + args.add(month),
+	}, filterConds(ctx, f, &args)...)
+	amount := summaryAmount(f, &args)
+	q := `
+		WITH t AS (
+			SELECT type, occurred_at, fx_convert(ledger_id, ` + amount + `, currency, $1, occurred_at) AS amount
 			FROM transactions
-			WHERE EXTRACT(YEAR FROM occurred_at) = $1
-			  AND EXTRACT(MONTH FROM occurred_at) = $2
+			WHERE ` + strings.Join(conds, " AND ") + `
 		)
-		SELECT COALESCE(income,0), COALESCE(expense,0), COALESCE(cnt,0), first_tx, last_tx FROM m;
+		SELECT
+			COALESCE(SUM(amount) FILTER (WHERE type = 'income'), 0),
+			COALESCE(SUM(amount) FILTER (WHERE type = 'expense'), 0),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE amount IS NULL),
+			MIN(occurred_at),
+			MAX(occurred_at)
+		FROM t;
 	`
 	var inc, exp int64
-	var cnt int
+	var cnt, missing int
 	var first, last sql.NullTime
-	if err := p.db.QueryRowContext(ctx, q, year, month).Scan(&inc, &exp, &cnt, &first, &last); err != nil {
+	err := p.db.QueryRowContext(ctx, q, args...).Scan(&inc, &exp, &cnt, &missing, &first, &last)
+	if err != nil {
 		return nil, err
 	}
+	if missing > 0 {
+		return nil, fmt.Errorf("%w: %d transaction(s) cannot be converted to %s", ErrMissingRate, missing, base)
+	}
 	ms := &MonthlySummary{
-		Year:    year,
-		Month:   month,
-		Income:  inc,
-		Expense: exp,
-		Net:     inc - exp,
-		CountTx: cnt,
+		Year:     year,
+		Month:    month,
+		Income:   inc,
+		Expense:  exp,
+		Net:      inc - exp,
+		Currency: base,
+		CountTx:  cnt,
 	}
 	if first.Valid {
 		ms.FirstTxDate = first.Time.Format(time.RFC3339)
@@ -95,5 +449,53 @@ func (p *pgRepo) MonthlySummary(ctx context.Context, year int, month int) (*Mont
 	if last.Valid {
 		ms.LastTxDate = last.Time.Format(time.RFC3339)
 	}
+	if ms.Categories, err = p.categoryTotals(ctx, strings.Join(conds, " AND "), f, args); err != nil {
+		return nil, err
+	}
+	ms.finishCategories()
 	return ms, nil
-}
\ No newline at end of file
+}
+
+// summaryAmount é o valor de cada transação nos resumos: com filtro de categoria, uma
+// transação rateada entra só com a soma das linhas dessa categoria (filterConds já garante
+// que alguma linha casa)
+func summaryAmount(f TxFilter, args *sqlArgs) string {
+	if f.Category == "" {
+		return "amount_cents"
+	}
+	return "COALESCE((SELECT SUM(sp.amount_cents) FROM transaction_splits sp" +
+		" WHERE sp.transaction_id = transactions.id AND sp.category = " + args.add(f.Category) + "), amount_cents)"
+}
+
+// categoryTotals agrupa por tipo e categoria com as mesmas condições do resumo, contando
+// cada linha de rateio na sua categoria (com filtro de categoria, só as linhas dela); as
+// cotações já foram conferidas pela consulta principal
+func (p *pgRepo) categoryTotals(ctx context.Context, where string, f TxFilter, args sqlArgs) ([]CategoryTotal, error) {
+	lines := ""
+	if f.Category != "" {
+		lines = "WHERE COALESCE(sp.category, t.category) = " + args.add(f.Category)
+	}
+	q := `
+		SELECT t.type, COALESCE(sp.category, t.category),
+			COALESCE(SUM(fx_convert(t.ledger_id, COALESCE(sp.amount_cents, t.amount_cents), t.currency, $1, t.occurred_at)), 0),
+			COUNT(*)
+		FROM (SELECT id, ledger_id, type, category, amount_cents, currency, occurred_at FROM transactions WHERE ` + where + `) t
+		LEFT JOIN transaction_splits sp ON sp.transaction_id = t.id
+		` + lines + `
+		GROUP BY 1, 2
+	`
+	rows, err := p.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []CategoryTotal
+	for rows.Next() {
+		var c CategoryTotal
+		if err := rows.Scan(&c.Type, &c.Category, &c.TotalCents, &c.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
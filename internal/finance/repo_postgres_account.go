@@ -0,0 +1,138 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// pgForeignKeyViolation é o SQLSTATE de violação de chave estrangeira
+const pgForeignKeyViolation = "23503"
+
+func isPgCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
+
+const accountColumns = `id, ledger_id, name, kind, currency, opening_balance_cents, COALESCE(closing_day, 0),
+	COALESCE(due_day, 0), created_at, updated_at`
+
+func scanAccount(sc rowScanner) (Account, error) {
+	var a Account
+	err := sc.Scan(&a.ID, &a.LedgerID, &a.Name, &a.Kind, &a.Currency, &a.OpeningBalanceCents, &a.ClosingDay, &a.DueDay,
+		&a.CreatedAt, &a.UpdatedAt)
+	return a, err
+}
+
+func (p *pgRepo) CreateAccount(ctx context.Context, a *Account) error {
+	const q = `
+		INSERT INTO accounts (id, ledger_id, name, kind, currency, opening_balance_cents, closing_day, due_day,
+			created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,NULLIF($7,0),NULLIF($8,0),$9,$10)
+	`
+	_, err := p.db.ExecContext(ctx, q,
+		a.ID, a.LedgerID, a.Name, a.Kind, a.Currency, a.OpeningBalanceCents, a.ClosingDay, a.DueDay, a.CreatedAt, a.UpdatedAt,
+	)
+	return err
+}
+
+func (p *pgRepo) GetAccount(ctx context.Context, id uuid.UUID) (*Account, error) {
+	q := `SELECT ` + accountColumns + ` FROM accounts WHERE id = $1 AND ledger_id = $2`
+	a, err := scanAccount(p.db.QueryRowContext(ctx, q, id, ledgerScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (p *pgRepo) ListAccounts(ctx context.Context) ([]Account, error) {
+	q := `SELECT ` + accountColumns + ` FROM accounts WHERE ledger_id = $1 ORDER BY name ASC`
+	rows, err := p.db.QueryContext(ctx, q, ledgerScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Account{}
+	for rows.Next() {
+		a, err := scanAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (p *pgRepo) UpdateAccount(ctx context.Context, a *Account) error {
+	const q = `
+		UPDATE accounts
+		SET name = $2, kind = $3, currency = $4, opening_balance_cents = $5, closing_day = NULLIF($6,0),
+			due_day = NULLIF($7,0), updated_at = $8
+		WHERE id = $1 AND ledger_id = $9
+		  AND (currency = $4 OR NOT (
+			EXISTS (SELECT 1 FROM transactions WHERE account_id = $1) OR
+			EXISTS (SELECT 1 FROM recurring_rules WHERE account_id = $1) OR
+			EXISTS (SELECT 1 FROM goals WHERE account_id = $1)))
+	`
+	res, err := p.db.ExecContext(ctx, q, a.ID, a.Name, a.Kind, a.Currency, a.OpeningBalanceCents, a.ClosingDay, a.DueDay,
+		a.UpdatedAt, ledgerScope(ctx))
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		// a conta existe: a troca de moeda foi barrada pelos lançamentos
+		if _, err := p.GetAccount(ctx, a.ID); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+	return nil
+}
+
+func (p *pgRepo) DeleteAccount(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM accounts WHERE id = $1 AND ledger_id = $2`, id, ledgerScope(ctx))
+	if isPgCode(err, pgForeignKeyViolation) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *pgRepo) AccountMovement(ctx context.Context, id uuid.UUID, at time.Time) (int64, error) {
+	const q = `
+		SELECT COALESCE(SUM(CASE
+			WHEN type = 'income'  THEN amount_cents
+			WHEN type = 'expense' THEN -amount_cents
+			WHEN direction = 'in'  THEN amount_cents
+			WHEN direction = 'out' THEN -amount_cents
+			ELSE 0 END), 0)
+		FROM transactions
+		WHERE account_id = $1 AND occurred_at <= $2 AND ledger_id = $3
+	`
+	var sum int64
+	if err := p.db.QueryRowContext(ctx, q, id, at, ledgerScope(ctx)).Scan(&sum); err != nil {
+		return 0, err
+	}
+	return sum, nil
+}
+
+func (p *pgRepo) StatementPayments(ctx context.Context, cardID uuid.UUID) ([]Transaction, error) {
+	q := `SELECT ` + txColumns + ` FROM transactions
+		WHERE account_id = $1 AND ledger_id = $2 AND direction = 'in' AND statement IS NOT NULL
+		ORDER BY occurred_at ASC`
+	return p.queryTxs(ctx, q, cardID, ledgerScope(ctx))
+}
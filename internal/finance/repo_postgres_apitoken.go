@@ -0,0 +1,87 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Os escopos são gravados como texto separado por espaços (como no OAuth)
+const apiTokenColumns = `id, user_id, name, token_hash, scopes, expires_at, last_used_at, created_at, revoked_at`
+
+func scanAPIToken(sc rowScanner) (APIToken, error) {
+	var t APIToken
+	var scopes string
+	err := sc.Scan(&t.ID, &t.UserID, &t.Name, &t.Hash, &scopes, &t.ExpiresAt, &t.LastUsedAt, &t.CreatedAt, &t.RevokedAt)
+	for _, s := range strings.Fields(scopes) {
+		t.Scopes = append(t.Scopes, Scope(s))
+	}
+	return t, err
+}
+
+func joinScopes(scopes []Scope) string {
+	parts := make([]string, len(scopes))
+	for i, s := range scopes {
+		parts[i] = string(s)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (p *pgRepo) CreateAPIToken(ctx context.Context, t *APIToken) error {
+	const q = `
+		INSERT INTO api_tokens (id, user_id, name, token_hash, scopes, expires_at, created_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+	`
+	_, err := p.db.ExecContext(ctx, q, t.ID, t.UserID, t.Name, t.Hash, joinScopes(t.Scopes), t.ExpiresAt, t.CreatedAt)
+	return err
+}
+
+func (p *pgRepo) GetAPITokenByHash(ctx context.Context, hash string) (*APIToken, error) {
+	t, err := scanAPIToken(p.db.QueryRowContext(ctx, `SELECT `+apiTokenColumns+` FROM api_tokens WHERE token_hash = $1`, hash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (p *pgRepo) ListAPITokens(ctx context.Context, user uuid.UUID) ([]APIToken, error) {
+	rows, err := p.db.QueryContext(ctx,
+		`SELECT `+apiTokenColumns+` FROM api_tokens WHERE user_id = $1 ORDER BY created_at DESC`, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []APIToken{}
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (p *pgRepo) RevokeAPIToken(ctx context.Context, user, id uuid.UUID, at time.Time) error {
+	const q = `UPDATE api_tokens SET revoked_at = COALESCE(revoked_at, $3) WHERE id = $1 AND user_id = $2`
+	res, err := p.db.ExecContext(ctx, q, id, user, at)
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *pgRepo) TouchAPIToken(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := p.db.ExecContext(ctx, `UPDATE api_tokens SET last_used_at = $2 WHERE id = $1`, id, at)
+	return err
+}
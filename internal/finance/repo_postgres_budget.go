@@ -0,0 +1,90 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Orçamentos recorrentes são gravados com month vazio
+const budgetColumns = `id, ledger_id, category_id, month, limit_cents, created_at, updated_at`
+
+func scanBudget(sc rowScanner) (Budget, error) {
+	var b Budget
+	err := sc.Scan(&b.ID, &b.LedgerID, &b.CategoryID, &b.Month, &b.LimitCents, &b.CreatedAt, &b.UpdatedAt)
+	return b, err
+}
+
+func (p *pgRepo) CreateBudget(ctx context.Context, b *Budget) error {
+	const q = `
+		INSERT INTO budgets (id, ledger_id, category_id, month, limit_cents, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+	`
+	_, err := p.db.ExecContext(ctx, q, b.ID, b.LedgerID, b.CategoryID, b.Month, b.LimitCents, b.CreatedAt, b.UpdatedAt)
+	if isPgCode(err, pgUniqueViolation) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (p *pgRepo) GetBudget(ctx context.Context, id uuid.UUID) (*Budget, error) {
+	q := `SELECT ` + budgetColumns + ` FROM budgets WHERE id = $1 AND ledger_id = $2`
+	b, err := scanBudget(p.db.QueryRowContext(ctx, q, id, ledgerScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (p *pgRepo) ListBudgets(ctx context.Context) ([]Budget, error) {
+	q := `SELECT ` + budgetColumns + ` FROM budgets WHERE ledger_id = $1 ORDER BY month ASC`
+	rows, err := p.db.QueryContext(ctx, q, ledgerScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Budget{}
+	for rows.Next() {
+		b, err := scanBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func (p *pgRepo) UpdateBudget(ctx context.Context, b *Budget) error {
+	const q = `
+		UPDATE budgets
+		SET category_id = $2, month = $3, limit_cents = $4, updated_at = $5
+		WHERE id = $1 AND ledger_id = $6
+	`
+	res, err := p.db.ExecContext(ctx, q, b.ID, b.CategoryID, b.Month, b.LimitCents, b.UpdatedAt, ledgerScope(ctx))
+	if isPgCode(err, pgUniqueViolation) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *pgRepo) DeleteBudget(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM budgets WHERE id = $1 AND ledger_id = $2`, id, ledgerScope(ctx))
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
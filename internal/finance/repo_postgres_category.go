@@ -0,0 +1,159 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Os apelidos são gravados como array JSON
+const categoryColumns = `id, ledger_id, parent_id, name, aliases, color, icon, created_at, updated_at`
+
+func scanCategory(sc rowScanner) (Category, error) {
+	var c Category
+	var aliases []byte
+	err := sc.Scan(&c.ID, &c.LedgerID, &c.ParentID, &c.Name, &aliases, &c.Color, &c.Icon, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return c, err
+	}
+	c.Aliases = []string{}
+	return c, json.Unmarshal(aliases, &c.Aliases)
+}
+
+// jsonStrings serializa listas gravadas em colunas JSONB (nil vira [])
+func jsonStrings(ss []string) []byte {
+	if ss == nil {
+		ss = []string{}
+	}
+	b, _ := json.Marshal(ss)
+	return b
+}
+
+func (p *pgRepo) CreateCategory(ctx context.Context, c *Category) error {
+	const q = `
+		INSERT INTO categories (id, ledger_id, parent_id, name, aliases, color, icon, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5::jsonb,$6,$7,$8,$9)
+	`
+	_, err := p.db.ExecContext(ctx, q,
+		c.ID, c.LedgerID, c.ParentID, c.Name, string(jsonStrings(c.Aliases)), c.Color, c.Icon, c.CreatedAt, c.UpdatedAt,
+	)
+	if isPgCode(err, pgUniqueViolation) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (p *pgRepo) GetCategory(ctx context.Context, id uuid.UUID) (*Category, error) {
+	q := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1 AND ledger_id = $2`
+	c, err := scanCategory(p.db.QueryRowContext(ctx, q, id, ledgerScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (p *pgRepo) ListCategories(ctx context.Context) ([]Category, error) {
+	q := `SELECT ` + categoryColumns + ` FROM categories WHERE ledger_id = $1 ORDER BY name ASC`
+	rows, err := p.db.QueryContext(ctx, q, ledgerScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Category{}
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// UpdateCategory renomeia as transações na mesma transação do banco
+func (p *pgRepo) UpdateCategory(ctx context.Context, c *Category, oldName string) error {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	const q = `
+		UPDATE categories
+		SET parent_id = $2, name = $3, aliases = $4::jsonb, color = $5, icon = $6, updated_at = $7
+		WHERE id = $1 AND ledger_id = $8
+	`
+	res, err := dbtx.ExecContext(ctx, q,
+		c.ID, c.ParentID, c.Name, string(jsonStrings(c.Aliases)), c.Color, c.Icon, c.UpdatedAt, ledgerScope(ctx),
+	)
+	if isPgCode(err, pgUniqueViolation) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	if c.Name != oldName {
+		const rename = `
+			UPDATE transactions SET category = $1
+			WHERE ledger_id = $2 AND category = $3 AND type <> 'transfer'
+		`
+		if _, err := dbtx.ExecContext(ctx, rename, c.Name, ledgerScope(ctx), oldName); err != nil {
+			return err
+		}
+		const renameSplits = `
+			UPDATE transaction_splits sp SET category = $1
+			FROM transactions t
+			WHERE t.id = sp.transaction_id AND t.ledger_id = $2 AND sp.category = $3
+		`
+		if _, err := dbtx.ExecContext(ctx, renameSplits, c.Name, ledgerScope(ctx), oldName); err != nil {
+			return err
+		}
+		const renameRules = `UPDATE recurring_rules SET category = $1 WHERE ledger_id = $2 AND category = $3`
+		if _, err := dbtx.ExecContext(ctx, renameRules, c.Name, ledgerScope(ctx), oldName); err != nil {
+			return err
+		}
+	}
+	return dbtx.Commit()
+}
+
+// DeleteCategory: subcategorias são barradas pela FK; transações, rateios e regras, pelo
+// NOT EXISTS
+func (p *pgRepo) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	const q = `
+		DELETE FROM categories c
+		WHERE c.id = $1 AND c.ledger_id = $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM transactions t
+			WHERE t.ledger_id = c.ledger_id AND t.category = c.name AND t.type <> 'transfer'
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM transaction_splits sp JOIN transactions t ON t.id = sp.transaction_id
+			WHERE t.ledger_id = c.ledger_id AND sp.category = c.name
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM recurring_rules r WHERE r.ledger_id = c.ledger_id AND r.category = c.name
+		  )
+	`
+	res, err := p.db.ExecContext(ctx, q, id, ledgerScope(ctx))
+	if isPgCode(err, pgForeignKeyViolation) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		if _, err := p.GetCategory(ctx, id); err != nil {
+			return err
+		}
+		return ErrConflict
+	}
+	return nil
+}
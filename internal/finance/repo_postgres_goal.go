@@ -0,0 +1,74 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Metas vinculadas a uma tag são gravadas com tag preenchida e account_id nulo
+const goalColumns = `id, ledger_id, name, target_cents, currency, target_date, account_id, tag, created_at, updated_at`
+
+func scanGoal(sc rowScanner) (Goal, error) {
+	var g Goal
+	err := sc.Scan(&g.ID, &g.LedgerID, &g.Name, &g.TargetCents, &g.Currency, &g.TargetDate, &g.AccountID, &g.Tag,
+		&g.CreatedAt, &g.UpdatedAt)
+	g.TargetDate = g.TargetDate.UTC()
+	return g, err
+}
+
+func (p *pgRepo) CreateGoal(ctx context.Context, g *Goal) error {
+	const q = `
+		INSERT INTO goals (id, ledger_id, name, target_cents, currency, target_date, account_id, tag, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+	`
+	_, err := p.db.ExecContext(ctx, q, g.ID, g.LedgerID, g.Name, g.TargetCents, g.Currency, g.TargetDate, g.AccountID, g.Tag,
+		g.CreatedAt, g.UpdatedAt)
+	if isPgCode(err, pgForeignKeyViolation) {
+		return accountRefErr(ErrNotFound)
+	}
+	return err
+}
+
+func (p *pgRepo) GetGoal(ctx context.Context, id uuid.UUID) (*Goal, error) {
+	q := `SELECT ` + goalColumns + ` FROM goals WHERE id = $1 AND ledger_id = $2`
+	g, err := scanGoal(p.db.QueryRowContext(ctx, q, id, ledgerScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (p *pgRepo) ListGoals(ctx context.Context) ([]Goal, error) {
+	q := `SELECT ` + goalColumns + ` FROM goals WHERE ledger_id = $1 ORDER BY target_date ASC`
+	rows, err := p.db.QueryContext(ctx, q, ledgerScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Goal{}
+	for rows.Next() {
+		g, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+func (p *pgRepo) DeleteGoal(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM goals WHERE id = $1 AND ledger_id = $2`, id, ledgerScope(ctx))
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+func (p *pgRepo) GetIdempotencyKey(ctx context.Context, key string, now time.Time) (*IdempotencyRecord, error) {
+	const q = `
+		SELECT key, request_hash, response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE ledger_id = $1 AND key = $2 AND expires_at > $3
+	`
+	var rec IdempotencyRecord
+	err := p.db.QueryRowContext(ctx, q, ledgerScope(ctx), key, now).
+		Scan(&rec.Key, &rec.RequestHash, &rec.Response, &rec.CreatedAt, &rec.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// CreateIdempotent reserva a chave antes de inserir a transação: uma requisição
+// concorrente com a mesma chave espera o commit e então não afeta nenhuma linha.
+// Chaves expiradas são reaproveitadas.
+func (p *pgRepo) CreateIdempotent(ctx context.Context, t *Transaction, rec *IdempotencyRecord) error {
+	const q = `
+		INSERT INTO idempotency_keys (ledger_id, key, request_hash, response, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (ledger_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, response = EXCLUDED.response,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+	`
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	res, err := dbtx.ExecContext(ctx, q, ledgerScope(ctx), rec.Key, rec.RequestHash, rec.Response, rec.CreatedAt, rec.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrConflict
+	}
+	if err := insertTx(ctx, dbtx, t); err != nil {
+		return err
+	}
+	return dbtx.Commit()
+}
+
+func (p *pgRepo) PurgeIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
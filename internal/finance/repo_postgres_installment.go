@@ -0,0 +1,121 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+const installmentColumns = `id, ledger_id, description, total_cents, currency, installments, remainder, first_at, status,
+	created_at, updated_at`
+
+func scanInstallment(sc rowScanner) (Installment, error) {
+	var p Installment
+	err := sc.Scan(&p.ID, &p.LedgerID, &p.Description, &p.TotalCents, &p.Currency, &p.Count, &p.Remainder, &p.FirstAt,
+		&p.Status, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+func (p *pgRepo) CreateInstallment(ctx context.Context, in *Installment, txs []*Transaction) error {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	const q = `
+		INSERT INTO installments (id, ledger_id, description, total_cents, currency, installments, remainder, first_at,
+			status, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	`
+	_, err = dbtx.ExecContext(ctx, q, in.ID, in.LedgerID, in.Description, in.TotalCents, in.Currency, in.Count,
+		in.Remainder, in.FirstAt, in.Status, in.CreatedAt, in.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err := insertTxs(ctx, dbtx, txs); err != nil {
+		return err
+	}
+	return dbtx.Commit()
+}
+
+func (p *pgRepo) GetInstallment(ctx context.Context, id uuid.UUID) (*Installment, error) {
+	q := `SELECT ` + installmentColumns + ` FROM installments WHERE id = $1 AND ledger_id = $2`
+	in, err := scanInstallment(p.db.QueryRowContext(ctx, q, id, ledgerScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+func (p *pgRepo) ListInstallments(ctx context.Context) ([]Installment, error) {
+	q := `SELECT ` + installmentColumns + ` FROM installments WHERE ledger_id = $1 ORDER BY first_at DESC`
+	rows, err := p.db.QueryContext(ctx, q, ledgerScope(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Installment{}
+	for rows.Next() {
+		in, err := scanInstallment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, in)
+	}
+	return out, rows.Err()
+}
+
+func (p *pgRepo) InstallmentTxs(ctx context.Context, id uuid.UUID) ([]Transaction, error) {
+	q := `SELECT ` + txColumns + ` FROM transactions WHERE installment_id = $1 AND ledger_id = $2
+		ORDER BY installment_number ASC`
+	txs, err := p.queryTxs(ctx, q, id, ledgerScope(ctx))
+	if txs == nil && err == nil {
+		txs = []Transaction{}
+	}
+	return txs, err
+}
+
+// SettleInstallment usa o status como trava: só uma chamada encerra uma compra ativa
+func (p *pgRepo) SettleInstallment(ctx context.Context, in *Installment, remove []uuid.UUID, add *Transaction) error {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	const q = `UPDATE installments SET status = $2, updated_at = $3 WHERE id = $1 AND ledger_id = $4 AND status = 'active'`
+	res, err := dbtx.ExecContext(ctx, q, in.ID, in.Status, in.UpdatedAt, ledgerScope(ctx))
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrConflict
+	}
+	for _, id := range remove {
+		if _, err := dbtx.ExecContext(ctx, `DELETE FROM transactions WHERE id = $1 AND installment_id = $2`, id, in.ID); err != nil {
+			return err
+		}
+	}
+	if add != nil {
+		if err := insertTx(ctx, dbtx, add); err != nil {
+			return err
+		}
+	}
+	return dbtx.Commit()
+}
+
+// DeleteInstallment conta com o ON DELETE CASCADE de transactions.installment_id
+func (p *pgRepo) DeleteInstallment(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM installments WHERE id = $1 AND ledger_id = $2`, id, ledgerScope(ctx))
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// insertLedger grava o livro e o primeiro owner (usado também no cadastro de usuários)
+func insertLedger(ctx context.Context, ex execer, l *Ledger, owner uuid.UUID) error {
+	if _, err := ex.ExecContext(ctx,
+		`INSERT INTO ledgers (id, name, created_at) VALUES ($1,$2,$3)`, l.ID, l.Name, l.CreatedAt); err != nil {
+		return err
+	}
+	_, err := ex.ExecContext(ctx,
+		`INSERT INTO ledger_members (ledger_id, user_id, role, created_at) VALUES ($1,$2,$3,$4)`,
+		l.ID, owner, RoleOwner, l.CreatedAt)
+	return err
+}
+
+func (p *pgRepo) CreateLedger(ctx context.Context, l *Ledger, owner uuid.UUID) error {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	if err := insertLedger(ctx, dbtx, l, owner); err != nil {
+		return err
+	}
+	return dbtx.Commit()
+}
+
+func (p *pgRepo) ListLedgers(ctx context.Context, user uuid.UUID) ([]Ledger, error) {
+	const q = `
+		SELECT l.id, l.name, m.role, l.created_at
+		FROM ledgers l
+		JOIN ledger_members m ON m.ledger_id = l.id
+		WHERE m.user_id = $1
+		ORDER BY l.name
+	`
+	rows, err := p.db.QueryContext(ctx, q, user)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Ledger{}
+	for rows.Next() {
+		var l Ledger
+		if err := rows.Scan(&l.ID, &l.Name, &l.Role, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+const memberQuery = `
+	SELECT m.ledger_id, m.user_id, u.email, m.role, m.created_at
+	FROM ledger_members m
+	JOIN users u ON u.id = m.user_id
+`
+
+func scanMember(sc rowScanner) (LedgerMember, error) {
+	var m LedgerMember
+	err := sc.Scan(&m.LedgerID, &m.UserID, &m.Email, &m.Role, &m.CreatedAt)
+	return m, err
+}
+
+func (p *pgRepo) GetMember(ctx context.Context, ledger, user uuid.UUID) (*LedgerMember, error) {
+	m, err := scanMember(p.db.QueryRowContext(ctx, memberQuery+` WHERE m.ledger_id = $1 AND m.user_id = $2`, ledger, user))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (p *pgRepo) ListMembers(ctx context.Context, ledger uuid.UUID) ([]LedgerMember, error) {
+	rows, err := p.db.QueryContext(ctx, memberQuery+` WHERE m.ledger_id = $1 ORDER BY u.email`, ledger)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []LedgerMember{}
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (p *pgRepo) AddMember(ctx context.Context, m *LedgerMember) error {
+	const q = `INSERT INTO ledger_members (ledger_id, user_id, role, created_at) VALUES ($1,$2,$3,$4)`
+	_, err := p.db.ExecContext(ctx, q, m.LedgerID, m.UserID, m.Role, m.CreatedAt)
+	if isPgCode(err, pgUniqueViolation) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (p *pgRepo) UpdateMember(ctx context.Context, m *LedgerMember) error {
+	res, err := p.db.ExecContext(ctx,
+		`UPDATE ledger_members SET role = $3 WHERE ledger_id = $1 AND user_id = $2`, m.LedgerID, m.UserID, m.Role)
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *pgRepo) RemoveMember(ctx context.Context, ledger, user uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM ledger_members WHERE ledger_id = $1 AND user_id = $2`, ledger, user)
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
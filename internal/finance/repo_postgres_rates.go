@@ -0,0 +1,50 @@
+package finance
+
+import (
+	"context"
+)
+
+func (p *pgRepo) UpsertRates(ctx context.Context, rates []ExchangeRate) error {
+	const q = `
+		INSERT INTO exchange_rates (ledger_id, from_currency, to_currency, rate_date, rate)
+		VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT (ledger_id, from_currency, to_currency, rate_date) DO UPDATE SET rate = EXCLUDED.rate
+	`
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	lid := ledgerScope(ctx)
+	for _, r := range rates {
+		if _, err := dbtx.ExecContext(ctx, q, lid, r.From, r.To, r.Date, r.Rate); err != nil {
+			return err
+		}
+	}
+	return dbtx.Commit()
+}
+
+func (p *pgRepo) ListRates(ctx context.Context, from, to string) ([]ExchangeRate, error) {
+	const q = `
+		SELECT from_currency, to_currency, rate_date, rate
+		FROM exchange_rates
+		WHERE ledger_id = $1
+		  AND ($2 = '' OR from_currency = $2)
+		  AND ($3 = '' OR to_currency = $3)
+		ORDER BY from_currency, to_currency, rate_date
+	`
+	rows, err := p.db.QueryContext(ctx, q, ledgerScope(ctx), from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []ExchangeRate{}
+	for rows.Next() {
+		var r ExchangeRate
+		if err := rows.Scan(&r.From, &r.To, &r.Date, &r.Rate); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,159 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// As datas puladas são gravadas como array JSON de "YYYY-MM-DD"
+const recurringColumns = `id, ledger_id, type, category, amount_cents, currency, description, account_id, frequency,
+	interval_count, day_of_month, start_at, until, occurrence_limit, skipped, done, next_at, created_at, updated_at, tags`
+
+func scanRecurring(sc rowScanner) (RecurringRule, error) {
+	var r RecurringRule
+	var skipped, tags []byte
+	err := sc.Scan(&r.ID, &r.LedgerID, &r.Type, &r.Category, &r.AmountCents, &r.Currency, &r.Description, &r.AccountID,
+		&r.Frequency, &r.Interval, &r.DayOfMonth, &r.StartAt, &r.Until, &r.Count, &skipped, &r.Done, &r.NextAt,
+		&r.CreatedAt, &r.UpdatedAt, &tags)
+	if err != nil {
+		return r, err
+	}
+	r.Skipped = []string{}
+	if err := json.Unmarshal(skipped, &r.Skipped); err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(tags, &r.Tags); err != nil {
+		return r, err
+	}
+	if len(r.Tags) == 0 {
+		r.Tags = nil
+	}
+	return r, nil
+}
+
+func (p *pgRepo) queryRecurring(ctx context.Context, q string, args ...any) ([]RecurringRule, error) {
+	rows, err := p.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []RecurringRule{}
+	for rows.Next() {
+		r, err := scanRecurring(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (p *pgRepo) CreateRecurring(ctx context.Context, r *RecurringRule) error {
+	const q = `
+		INSERT INTO recurring_rules (id, ledger_id, type, category, amount_cents, currency, description, account_id, frequency,
+			interval_count, day_of_month, start_at, until, occurrence_limit, skipped, done, next_at, created_at, updated_at, tags)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15::jsonb,$16,$17,$18,$19,$20::jsonb)
+	`
+	_, err := p.db.ExecContext(ctx, q,
+		r.ID, r.LedgerID, r.Type, r.Category, r.AmountCents, r.Currency, r.Description, r.AccountID, r.Frequency,
+		r.Interval, r.DayOfMonth, r.StartAt, r.Until, r.Count, string(jsonStrings(r.Skipped)), r.Done, r.NextAt,
+		r.CreatedAt, r.UpdatedAt, string(jsonStrings(r.Tags)),
+	)
+	return err
+}
+
+func (p *pgRepo) GetRecurring(ctx context.Context, id uuid.UUID) (*RecurringRule, error) {
+	q := `SELECT ` + recurringColumns + ` FROM recurring_rules WHERE id = $1 AND ledger_id = $2`
+	r, err := scanRecurring(p.db.QueryRowContext(ctx, q, id, ledgerScope(ctx)))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (p *pgRepo) ListRecurring(ctx context.Context) ([]RecurringRule, error) {
+	return p.queryRecurring(ctx,
+		`SELECT `+recurringColumns+` FROM recurring_rules WHERE ledger_id = $1 ORDER BY created_at ASC`, ledgerScope(ctx))
+}
+
+func (p *pgRepo) UpdateRecurring(ctx context.Context, r *RecurringRule) error {
+	const q = `UPDATE recurring_rules SET skipped = $2::jsonb, updated_at = $3 WHERE id = $1 AND ledger_id = $4`
+	res, err := p.db.ExecContext(ctx, q, r.ID, string(jsonStrings(r.Skipped)), r.UpdatedAt, ledgerScope(ctx))
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *pgRepo) DeleteRecurring(ctx context.Context, id uuid.UUID) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM recurring_rules WHERE id = $1 AND ledger_id = $2`, id, ledgerScope(ctx))
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *pgRepo) DueRecurring(ctx context.Context, at time.Time) ([]RecurringRule, error) {
+	return p.queryRecurring(ctx,
+		`SELECT `+recurringColumns+` FROM recurring_rules WHERE next_at <= $1 ORDER BY next_at ASC`, at)
+}
+
+// AdvanceRecurring usa done como versão: o UPDATE só acontece se ninguém avançou antes
+func (p *pgRepo) AdvanceRecurring(ctx context.Context, r *RecurringRule, prevDone int, tx *Transaction) error {
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	const q = `UPDATE recurring_rules SET done = $2, next_at = $3, updated_at = $4 WHERE id = $1 AND done = $5`
+	res, err := dbtx.ExecContext(ctx, q, r.ID, r.Done, r.NextAt, r.UpdatedAt, prevDone)
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return ErrConflict
+	}
+	if tx != nil {
+		if err := insertTx(ctx, dbtx, tx); err != nil {
+			return err
+		}
+	}
+	return dbtx.Commit()
+}
+
+// RunExclusive segura um advisory lock de sessão em uma conexão dedicada enquanto fn roda,
+// de modo que só uma instância (entre todas as do ASG) execute por vez
+func (p *pgRepo) RunExclusive(ctx context.Context, name string, fn func(context.Context) error) (bool, error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	h := fnv.New64a()
+	h.Write([]byte("finance-tracker:" + name))
+	key := int64(h.Sum64())
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, key)
+	return true, fn(ctx)
+}
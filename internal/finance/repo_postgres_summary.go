@@ -0,0 +1,67 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RangeSummary agrupa com date_trunc em UTC; g já foi validada pelo Service e entra como
+// literal para que categoryTotals possa reaproveitar os mesmos argumentos
+func (p *pgRepo) RangeSummary(ctx context.Context, from, to time.Time, g Granularity, f TxFilter, base string) (*RangeSummary, error) {
+	if !g.valid() {
+		return nil, ErrBadRequest
+	}
+	args := sqlArgs{base}
+	conds := append([]string{
+		"type <> 'transfer'",
+		"occurred_at >= " + args.add(from),
+		"occurred_at <= " + args.add(to),
+	}, filterConds(ctx, f, &args)...)
+	where := strings.Join(conds, " AND ")
+	amount := summaryAmount(f, &args)
+	q := `
+		WITH t AS (
+			SELECT date_trunc('` + string(g) + `', occurred_at AT TIME ZONE 'UTC') AS bucket, type,
+				fx_convert(ledger_id, ` + amount + `, currency, $1, occurred_at) AS amount
+			FROM transactions
+			WHERE ` + where + `
+		)
+		SELECT bucket,
+			COALESCE(SUM(amount) FILTER (WHERE type = 'income'), 0),
+			COALESCE(SUM(amount) FILTER (WHERE type = 'expense'), 0),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE amount IS NULL)
+		FROM t
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+	rows, err := p.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	rs := &RangeSummary{}
+	missing := 0
+	for rows.Next() {
+		var b SummaryBucket
+		var m int
+		if err := rows.Scan(&b.Start, &b.Income, &b.Expense, &b.CountTx, &m); err != nil {
+			return nil, err
+		}
+		missing += m
+		b.Start = b.Start.UTC()
+		rs.Buckets = append(rs.Buckets, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if missing > 0 {
+		return nil, fmt.Errorf("%w: %d transaction(s) cannot be converted to %s", ErrMissingRate, missing, base)
+	}
+	if rs.Categories, err = p.categoryTotals(ctx, where, f, args); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
@@ -0,0 +1,55 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (p *pgRepo) TagTotals(ctx context.Context, from, to time.Time, f TxFilter, base string) ([]TagTotal, error) {
+	args := sqlArgs{base}
+	conds := append([]string{
+		"type <> 'transfer'",
+		"occurred_at >= " + args.add(from),
+		"occurred_at <= " + args.add(to),
+	}, filterConds(ctx, f, &args)...)
+	amount := summaryAmount(f, &args)
+	q := `
+		WITH t AS (
+			SELECT id, type, fx_convert(ledger_id, ` + amount + `, currency, $1, occurred_at) AS amount
+			FROM transactions
+			WHERE ` + strings.Join(conds, " AND ") + `
+		)
+		SELECT tt.tag,
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'income'), 0),
+			COALESCE(SUM(t.amount) FILTER (WHERE t.type = 'expense'), 0),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE t.amount IS NULL)
+		FROM t JOIN transaction_tags tt ON tt.transaction_id = t.id
+		GROUP BY tt.tag
+	`
+	rows, err := p.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []TagTotal
+	missing := 0
+	for rows.Next() {
+		var tt TagTotal
+		var m int
+		if err := rows.Scan(&tt.Tag, &tt.IncomeCents, &tt.ExpenseCents, &tt.Count, &m); err != nil {
+			return nil, err
+		}
+		missing += m
+		out = append(out, tt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if missing > 0 {
+		return nil, fmt.Errorf("%w: tagged transaction(s) cannot be converted to %s", ErrMissingRate, base)
+	}
+	return out, nil
+}
@@ -0,0 +1,55 @@
+package finance
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// pgUniqueViolation é o SQLSTATE de violação de unicidade
+const pgUniqueViolation = "23505"
+
+const userColumns = `id, email, password_hash, created_at`
+
+func scanUser(sc rowScanner) (*User, error) {
+	var u User
+	err := sc.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (p *pgRepo) CreateUser(ctx context.Context, u *User) error {
+	const q = `INSERT INTO users (id, email, password_hash, created_at) VALUES ($1,$2,$3,$4)`
+	dbtx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer dbtx.Rollback()
+	_, err = dbtx.ExecContext(ctx, q, u.ID, u.Email, u.PasswordHash, u.CreatedAt)
+	if isPgCode(err, pgUniqueViolation) {
+		return ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+	personal := &Ledger{ID: u.ID, Name: PersonalLedgerName, CreatedAt: u.CreatedAt}
+	if err := insertLedger(ctx, dbtx, personal, u.ID); err != nil {
+		return err
+	}
+	return dbtx.Commit()
+}
+
+func (p *pgRepo) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
+	return scanUser(p.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id))
+}
+
+func (p *pgRepo) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	return scanUser(p.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1`, email))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,83 +14,363 @@ import (
 var (
 	ErrNotFound   = errors.New("not found")
 	ErrBadRequest = errors.New("bad request")
+	ErrConflict   = errors.New("conflict")
 )
 
 type Repository interface {
 	Create(ctx context.Context, t *Transaction) error
-	ListByPeriod(ctx context.Context, from, to time.Time) ([]Transaction, error)
+	// CreateExternal grava t a menos que já exista transação com o mesmo ExternalID na
+	// mesma conta; inserted=false indica duplicata
+	CreateExternal(ctx context.Context, t *Transaction) (inserted bool, err error)
+	// CreateBatch grava todas as transações em uma única transação do banco
+	CreateBatch(ctx context.Context, ts []*Transaction) error
+	// CreateTransfer grava as duas pernas atomicamente
+	CreateTransfer(ctx context.Context, out, in *Transaction) error
+	Get(ctx context.Context, id uuid.UUID) (*Transaction, error)
+	Update(ctx context.Context, t *Transaction) error
+	ListByPeriod(ctx context.Context, from, to time.Time, f TxFilter) ([]Transaction, error)
+	// List devolve até q.Limit itens após q.After na ordem pedida
+	List(ctx context.Context, q TxQuery) ([]Transaction, error)
 	Delete(ctx context.Context, id uuid.UUID) error
-	MonthlySummary(ctx context.Context, year int, month int) (*MonthlySummary, error)
+	// MonthlySummary converte os valores para a moeda base; ErrMissingRate se faltar cotação
+	MonthlySummary(ctx context.Context, year int, month int, f TxFilter, base string) (*MonthlySummary, error)
+
+	AccountRepository
+	RateRepository
+	IdempotencyRepository
+	UserRepository
+	LedgerRepository
+	APITokenRepository
+	CategoryRepository
+	BudgetRepository
+	RecurringRepository
+	InstallmentRepository
+	TagRepository
+	SummaryRepository
+	GoalRepository
 }
 
+// DefaultMaxFuture é o quanto OccurredAt pode estar à frente do relógio do servidor
+const DefaultMaxFuture = 24 * time.Hour
+
 type Service struct {
-	repo Repository
+	repo           Repository
+	maxFuture      time.Duration
+	baseCurrency   string
+	idempotencyTTL time.Duration
+	authSecret     []byte
+	tokenTTL       time.Duration
+
+	autoCreateCategories bool
 }
 
-func NewService(r Repository) *Service { return &Service{repo: r} }
+// Option configura parâmetros opcionais do Service
+type Option func(*Service)
 
-func (s *Service) Create(ctx context.Context, typ TxType, category string, amountCents int64, desc string) (*Transaction, error) {
-	if typ != Income && typ != Expense {
-		return nil, ErrBadRequest
+// WithMaxFuture define o limite para datas futuras em OccurredAt
+func WithMaxFuture(d time.Duration) Option {
+	return func(s *Service) { s.maxFuture = d }
+}
+
+func NewService(r Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:           r,
+		maxFuture:      DefaultMaxFuture,
+		baseCurrency:   DefaultCurrency,
+		idempotencyTTL: DefaultIdempotencyTTL,
+		authSecret:     randomSecret(),
+		tokenTTL:       DefaultTokenTTL,
 	}
-	category = strings.TrimSpace(category)
-	if category == "" || amountCents <= 0 {
-		return nil, ErrBadRequest
+	for _, o := range opts {
+		o(s)
+	}
+	return s
+}
+
+// TxInput reúne os dados para criar uma transação; OccurredAt zero significa "agora" e
+// Currency vazia herda a moeda da conta (ou a moeda base, sem conta)
+type TxInput struct {
+	Type        TxType
+	Category    string
+	AmountCents int64
+	Currency    string
+	Description string
+	OccurredAt  time.Time
+	AccountID   *uuid.UUID
+	Tags        []string
+	Splits      []Split // rateio opcional; sem Category, vale a categoria da primeira linha
+}
+
+// TxPatch descreve uma alteração parcial; campos nil não são modificados
+type TxPatch struct {
+	Type        *TxType
+	Category    *string
+	AmountCents *int64
+	Description *string
+	OccurredAt  *time.Time
+	AccountID   *uuid.UUID
+	Currency    *string
+	Tags        *[]string // substitui todas as tags
+	Splits      *[]Split  // substitui o rateio; vazio remove
+}
+
+func (s *Service) validateTx(t *Transaction, now time.Time) error {
+	if t.Type != Income && t.Type != Expense {
+		return fmt.Errorf("%w: type must be income or expense", ErrBadRequest)
+	}
+	if t.Category == "" {
+		return fmt.Errorf("%w: category is required", ErrBadRequest)
+	}
+	if t.AmountCents <= 0 {
+		return fmt.Errorf("%w: amount_cents must be positive", ErrBadRequest)
+	}
+	// parcelas são lançadas nos meses seguintes de propósito
+	if t.InstallmentID == nil && t.OccurredAt.After(now.Add(s.maxFuture)) {
+		return fmt.Errorf("%w: occurred_at too far in the future", ErrBadRequest)
+	}
+	return validateSplits(t)
+}
+
+// resolveAccount garante que a conta referenciada (se houver) existe e define a moeda:
+// transações com conta usam obrigatoriamente a moeda da conta
+func (s *Service) resolveAccount(ctx context.Context, t *Transaction) error {
+	if t.Currency != "" {
+		cur, ok := normalizeCurrency(t.Currency)
+		if !ok {
+			return fmt.Errorf("%w: invalid currency %q", ErrBadRequest, t.Currency)
+		}
+		t.Currency = cur
+	}
+	if t.AccountID == nil {
+		if t.Currency == "" {
+			t.Currency = s.baseCurrency
+		}
+		return nil
+	}
+	a, err := s.repo.GetAccount(ctx, *t.AccountID)
+	if err != nil {
+		return accountRefErr(err)
+	}
+	if t.Currency == "" {
+		t.Currency = a.Currency
+	} else if t.Currency != a.Currency {
+		return fmt.Errorf("%w: currency must match account currency %s", ErrBadRequest, a.Currency)
+	}
+	return nil
+}
+
+func (s *Service) Create(ctx context.Context, in TxInput) (*Transaction, error) {
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.newTx(ctx, in, time.Now().UTC(), cats)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saveCategories(ctx, cats, tx); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// newTx monta e valida a transação sem persistir (regras de Create). A categoria é
+// resolvida em cats; as criadas no modo auto-create só são gravadas por saveCategories.
+func (s *Service) newTx(ctx context.Context, in TxInput, now time.Time, cats *categorySet) (*Transaction, error) {
+	occurred := in.OccurredAt.UTC()
+	if in.OccurredAt.IsZero() {
+		occurred = now
 	}
-	now := time.Now().UTC()
 	tx := &Transaction{
 		ID:          uuid.New(),
-		Type:        typ,
-		Category:    category,
-		AmountCents: amountCents,
-		OccurredAt:  now,
-		Description: strings.TrimSpace(desc),
+		LedgerID:    ledgerScope(ctx),
+		Type:        in.Type,
+		Category:    strings.TrimSpace(in.Category),
+		AmountCents: in.AmountCents,
+		Currency:    in.Currency,
+		OccurredAt:  occurred,
+		Description: strings.TrimSpace(in.Description),
+		AccountID:   in.AccountID,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
-	if err := s.repo.Create(ctx, tx); err != nil {
+	var err error
+	if tx.Splits, err = cats.resolveSplits(in.Splits, now); err != nil {
+		return nil, err
+	}
+	if tx.Category == "" && len(tx.Splits) > 0 {
+		tx.Category = tx.Splits[0].Category
+	}
+	if err := s.validateTx(tx, now); err != nil {
+		return nil, err
+	}
+	if tx.Tags, err = normalizeTags(in.Tags); err != nil {
+		return nil, err
+	}
+	if tx.Category, err = cats.resolve(tx.Category, now); err != nil {
+		return nil, err
+	}
+	if err := s.resolveAccount(ctx, tx); err != nil {
 		return nil, err
 	}
 	return tx, nil
 }
 
-func (s *Service) ListByPeriod(ctx context.Context, from, to time.Time) ([]Transaction, error) {
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Transaction, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Update aplica o patch sobre a transação existente com as mesmas regras do Create
+func (s *Service) Update(ctx context.Context, id uuid.UUID, p TxPatch) (*Transaction, error) {
+	tx, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Type == Transfer {
+		return nil, fmt.Errorf("%w: transfers cannot be edited, delete and recreate", ErrBadRequest)
+	}
+	changed := false
+	if p.Type != nil && *p.Type != tx.Type {
+		tx.Type = *p.Type
+		changed = true
+	}
+	var cats *categorySet
+	if p.Category != nil && categoryKey(*p.Category) != categoryKey(tx.Category) {
+		if cats, err = s.loadCategories(ctx); err != nil {
+			return nil, err
+		}
+		if tx.Category, err = cats.resolve(*p.Category, time.Now().UTC()); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if p.AmountCents != nil && *p.AmountCents != tx.AmountCents {
+		tx.AmountCents = *p.AmountCents
+		changed = true
+	}
+	if p.Description != nil {
+		if d := strings.TrimSpace(*p.Description); d != tx.Description {
+			tx.Description = d
+			changed = true
+		}
+	}
+	if p.OccurredAt != nil && !p.OccurredAt.Equal(tx.OccurredAt) {
+		tx.OccurredAt = p.OccurredAt.UTC()
+		changed = true
+	}
+	if p.AccountID != nil && (tx.AccountID == nil || *tx.AccountID != *p.AccountID) {
+		tx.AccountID = p.AccountID
+		changed = true
+	}
+	if p.Currency != nil && *p.Currency != tx.Currency {
+		tx.Currency = *p.Currency
+		changed = true
+	}
+	if p.Splits != nil {
+		if cats == nil {
+			if cats, err = s.loadCategories(ctx); err != nil {
+				return nil, err
+			}
+		}
+		splits, err := cats.resolveSplits(*p.Splits, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(splits, tx.Splits) {
+			tx.Splits = splits
+			changed = true
+		}
+	}
+	if p.Tags != nil {
+		tags, err := normalizeTags(*p.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(tags, tx.Tags) {
+			tx.Tags = tags
+			changed = true
+		}
+	}
+	if p.AccountID != nil || p.Currency != nil {
+		if err := s.resolveAccount(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now().UTC()
+	if err := s.validateTx(tx, now); err != nil {
+		return nil, err
+	}
+	if !changed {
+		return tx, nil
+	}
+	tx.UpdatedAt = now
+	if cats != nil {
+		if err := s.saveCategories(ctx, cats, tx); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Update(ctx, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (s *Service) ListByPeriod(ctx context.Context, from, to time.Time, f TxFilter) ([]Transaction, error) {
 	if to.Before(from) {
 		return nil, ErrBadRequest
 	}
-	return s.repo.ListByPeriod(ctx, from.UTC(), to.UTC())
+	if err := f.normalizeTags(); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByPeriod(ctx, from.UTC(), to.UTC(), f)
 }
 
+// Delete remove a transação; pernas de transferência são removidas em par
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *Service) MonthlySummary(ctx context.Context, year int, month int) (*MonthlySummary, error) {
+// MonthlySummary totaliza o mês convertendo cada transação para "base" (vazio = moeda base)
+// com a cotação vigente na data da transação
+func (s *Service) MonthlySummary(ctx context.Context, year int, month int, f TxFilter, base string) (*MonthlySummary, error) {
 	if month < 1 || month > 12 {
 		return nil, ErrBadRequest
 	}
-	return s.repo.MonthlySummary(ctx, year, month)
+	base, err := s.resolveBase(base)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.normalizeTags(); err != nil {
+		return nil, err
+	}
+	ms, err := s.repo.MonthlySummary(ctx, year, month, f, base)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.categoryPaths(ctx, ms.Categories); err != nil {
+		return nil, err
+	}
+	return ms, nil
+}
+
+// monthNames são os nomes dos meses em português, usados nos relatórios
+var monthNames = map[time.Month]string{
+	1: "Janeiro", 2: "Fevereiro", 3: "Março", 4: "Abril",
+	5: "Maio", 6: "Junho", 7: "Julho", 8: "Agosto",
+	9: "Setembro", 10: "Outubro", 11: "Novembro", 12: "Dezembro",
 }
 
 // GenerateMonthlyReport cria um relatório textual formatado do mês
-func (s *Service) GenerateMonthlyReport(ctx context.Context, year int, month int) (string, error) {
-	summary, err := s.MonthlySummary(ctx, year, month)
+func (s *Service) GenerateMonthlyReport(ctx context.Context, year int, month int, base string) (string, error) {
+	summary, err := s.MonthlySummary(ctx, year, month, TxFilter{}, base)
 	if err != nil {
 		return "", err
 	}
-
-	// Formatar valores em reais
-	incomeReais := float64(summary.Income) / 100.0
-	expenseReais := float64(summary.Expense) / 100.0
-	netReais := float64(summary.Net) / 100.0
-
-	// Mapa de meses em português
-	monthNames := map[int]string{
-		1: "Janeiro", 2: "Fevereiro", 3: "Março", 4: "Abril",
-		5: "Maio", 6: "Junho", 7: "Julho", 8: "Agosto",
-		9: "Setembro", 10: "Outubro", 11: "Novembro", 12: "Dezembro",
-	}
-	monthName := monthNames[month]
+	cur := summary.Currency
+	monthName := monthNames[time.Month(month)]
 
 	// Gerar relatório formatado
 	report := fmt.Sprintf(`========================================
@@ -97,17 +378,28 @@ RELATÓRIO FINANCEIRO - %s/%d
 ========================================
 
 Período: %s de %d
+Moeda: %s
 Total de Transações: %d
 
 RESUMO FINANCEIRO:
 ------------------------------------------
-Receitas:       R$ %.2f
-Despesas:       R$ %.2f
+Receitas:       %s
+Despesas:       %s
 ------------------------------------------
-Saldo Final:    R$ %.2f
+Saldo Final:    %s
 ------------------------------------------
 
-`, monthName, year, monthName, year, summary.CountTx, incomeReais, expenseReais, netReais)
+`, monthName, year, monthName, year, cur, summary.CountTx,
+		formatMoney(summary.Income, cur), formatMoney(summary.Expense, cur), formatMoney(summary.Net, cur))
+
+	report += categoryTable("RECEITAS POR CATEGORIA", Income, summary.Categories, cur)
+	report += categoryTable("DESPESAS POR CATEGORIA", Expense, summary.Categories, cur)
+
+	comparison, err := s.comparisonSection(ctx, year, month, cur)
+	if err != nil {
+		return "", err
+	}
+	report += comparison
 
 	if summary.FirstTxDate != "" {
 		report += fmt.Sprintf("Primeira Transação: %s\n", summary.FirstTxDate)
@@ -117,13 +409,38 @@ Saldo Final:    R$ %.2f
 	}
 
 	status := "POSITIVO ✓"
-	if netReais < 0 {
+	if summary.Net < 0 {
 		status = "NEGATIVO ✗"
-	} else if netReais == 0 {
+	} else if summary.Net == 0 {
 		status = "NEUTRO"
 	}
 	report += fmt.Sprintf("\nStatus: %s\n", status)
 	report += "========================================\n"
 
 	return report, nil
-}
\ No newline at end of file
+}
+
+// categoryTable formata o detalhamento de um tipo como tabela do relatório; vazio se não
+// houver lançamentos do tipo
+func categoryTable(title string, typ TxType, cats []CategoryTotal, cur string) string {
+	var b strings.Builder
+	for _, c := range cats {
+		if c.Type != typ {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(title + ":\n")
+			b.WriteString("------------------------------------------\n")
+			fmt.Fprintf(&b, "%-20s %14s %4s %7s\n", "Categoria", "Valor", "Qtd", "%")
+		}
+		name := c.Category
+		if r := []rune(name); len(r) > 20 {
+			name = string(r[:19]) + "…"
+		}
+		fmt.Fprintf(&b, "%-20s %14s %4d %6.1f%%\n", name, formatMoney(c.TotalCents, cur), c.Count, c.Percent)
+	}
+	if b.Len() > 0 {
+		b.WriteString("------------------------------------------\n\n")
+	}
+	return b.String()
+}
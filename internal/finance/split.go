@@ -0,0 +1,100 @@
+package finance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxSplits limita as linhas de rateio de uma transação
+const maxSplits = 50
+
+// Split é uma linha de rateio: parte do valor da transação atribuída a outra categoria
+// (ex: uma compra de mercado dividida entre alimentação, limpeza e farmácia)
+type Split struct {
+	Category    string `json:"category"`
+	AmountCents int64  `json:"amount_cents"`
+	Note        string `json:"note,omitempty"`
+}
+
+// validateSplits confere o rateio: ao menos duas linhas, valores positivos e soma igual ao
+// valor da transação
+func validateSplits(t *Transaction) error {
+	if len(t.Splits) == 0 {
+		return nil
+	}
+	if len(t.Splits) < 2 || len(t.Splits) > maxSplits {
+		return fmt.Errorf("%w: splits must have 2 to %d lines", ErrBadRequest, maxSplits)
+	}
+	var sum int64
+	for _, sp := range t.Splits {
+		if sp.Category == "" {
+			return fmt.Errorf("%w: every split needs a category", ErrBadRequest)
+		}
+		if sp.AmountCents <= 0 {
+			return fmt.Errorf("%w: split amount_cents must be positive", ErrBadRequest)
+		}
+		sum += sp.AmountCents
+	}
+	if sum != t.AmountCents {
+		return fmt.Errorf("%w: splits add up to %d, amount_cents is %d", ErrBadRequest, sum, t.AmountCents)
+	}
+	return nil
+}
+
+// resolveSplits copia as linhas (aparando os textos) e resolve as categorias em cats
+func (cs *categorySet) resolveSplits(in []Split, now time.Time) ([]Split, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make([]Split, len(in))
+	for i, sp := range in {
+		sp.Category = strings.TrimSpace(sp.Category)
+		sp.Note = strings.TrimSpace(sp.Note)
+		if sp.Category != "" {
+			var err error
+			if sp.Category, err = cs.resolve(sp.Category, now); err != nil {
+				return nil, err
+			}
+		}
+		out[i] = sp
+	}
+	return out, nil
+}
+
+// categoryLines devolve as partes da transação por categoria: as linhas do rateio ou, sem
+// rateio, a própria transação
+func (t *Transaction) categoryLines() []Split {
+	if len(t.Splits) > 0 {
+		return t.Splits
+	}
+	return []Split{{Category: t.Category, AmountCents: t.AmountCents}}
+}
+
+// summaryPart devolve a transação como ela entra nos resumos: com filtro de categoria, uma
+// transação rateada conta só com as linhas dessa categoria (a listagem continua trazendo a
+// transação inteira)
+func (f TxFilter) summaryPart(t *Transaction) *Transaction {
+	if f.Category == "" || len(t.Splits) == 0 {
+		return t
+	}
+	part := *t
+	part.Splits, part.AmountCents = nil, 0
+	for _, sp := range t.Splits {
+		if sp.Category == f.Category {
+			part.Splits = append(part.Splits, sp)
+			part.AmountCents += sp.AmountCents
+		}
+	}
+	return &part
+}
+
+// hasCategory indica se a transação (ou alguma linha do rateio) é da categoria
+func (t *Transaction) hasCategory(category string) bool {
+	for _, sp := range t.categoryLines() {
+		if sp.Category == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_Splits(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	at := time.Date(2025, 6, 5, 12, 0, 0, 0, time.UTC)
+
+	tx, err := s.Create(ctx, TxInput{Type: Expense, AmountCents: 30000, OccurredAt: at, Splits: []Split{
+		{Category: "Mercado", AmountCents: 20000},
+		{Category: "Farmácia", AmountCents: 10000, Note: " remédios "},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Category != "Mercado" || len(tx.Splits) != 2 || tx.Splits[1].Note != "remédios" {
+		t.Fatalf("unexpected split transaction: %+v", tx)
+	}
+	if _, err := s.Create(ctx, TxInput{Type: Expense, Category: "Mercado", AmountCents: 5000, OccurredAt: at}); err != nil {
+		t.Fatal(err)
+	}
+	bad := TxInput{Type: Expense, AmountCents: 30001, OccurredAt: at, Splits: tx.Splits}
+	if _, err := s.Create(ctx, bad); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("splits not adding up: %v", err)
+	}
+	bad.AmountCents, bad.Splits = 20000, tx.Splits[:1]
+	if _, err := s.Create(ctx, bad); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("single split: %v", err)
+	}
+
+	sum, err := s.MonthlySummary(ctx, 2025, 6, TxFilter{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	totals := map[string]int64{}
+	for _, ct := range sum.Categories {
+		totals[ct.Category] = ct.TotalCents
+	}
+	if sum.Expense != 35000 || totals["Mercado"] != 25000 || totals["Farmácia"] != 10000 {
+		t.Errorf("summary: %+v", sum)
+	}
+	got, _ := s.ListByPeriod(ctx, at.Add(-time.Hour), at.Add(time.Hour), TxFilter{Category: "Farmácia"})
+	if len(got) != 1 || got[0].ID != tx.ID {
+		t.Errorf("category filter: %+v", got)
+	}
+	// nos resumos, o filtro de categoria conta só as linhas dela
+	sum, err = s.MonthlySummary(ctx, 2025, 6, TxFilter{Category: "Farmácia"}, "")
+	if err != nil || sum.Expense != 10000 || sum.CountTx != 1 || len(sum.Categories) != 1 || sum.Categories[0].Category != "Farmácia" {
+		t.Errorf("filtered summary: %+v %v", sum, err)
+	}
+	rs, err := s.RangeSummary(ctx, at.AddDate(0, 0, -4), at, GranularityMonth, TxFilter{Category: "Mercado"}, "")
+	if err != nil || rs.Expense != 25000 || len(rs.Categories) != 1 || rs.Categories[0].TotalCents != 25000 {
+		t.Errorf("filtered range summary: %+v %v", rs, err)
+	}
+
+	cats, _ := s.ListCategories(ctx)
+	for _, c := range cats {
+		if c.Name != "Farmácia" {
+			continue
+		}
+		if err := s.DeleteCategory(ctx, c.ID); !errors.Is(err, ErrConflict) {
+			t.Errorf("delete category in use by split: %v", err)
+		}
+		name := "Saúde"
+		if _, err := s.UpdateCategory(ctx, c.ID, CategoryPatch{Name: &name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tx, err = s.Get(ctx, tx.ID); err != nil || tx.Splits[1].Category != "Saúde" {
+		t.Errorf("rename not propagated: %+v %v", tx, err)
+	}
+
+	none := []Split{}
+	if tx, err = s.Update(ctx, tx.ID, TxPatch{Splits: &none}); err != nil || tx.Splits != nil {
+		t.Errorf("splits not removed: %+v %v", tx, err)
+	}
+}
@@ -0,0 +1,211 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxStatements limita quantas faturas uma listagem devolve
+const maxStatements = 36
+
+type StatementStatus string
+
+const (
+	StatementOpen    StatementStatus = "open"    // ciclo em andamento
+	StatementClosed  StatementStatus = "closed"  // fechada, aguardando pagamento
+	StatementPaid    StatementStatus = "paid"    // pagamentos cobrem o total
+	StatementOverdue StatementStatus = "overdue" // vencida sem pagamento integral
+)
+
+// Statement é a fatura de um cartão identificada pelo mês do fechamento (Period). Entram
+// as receitas e despesas do cartão de OpensAt (inclusive) a ClosesAt (exclusive): compras
+// no dia do fechamento já vão para a fatura seguinte.
+type Statement struct {
+	AccountID  uuid.UUID       `json:"account_id"`
+	Period     string          `json:"period"` // YYYY-MM
+	Currency   string          `json:"currency"`
+	OpensAt    time.Time       `json:"opens_at"`
+	ClosesAt   time.Time       `json:"closes_at"`
+	DueAt      time.Time       `json:"due_at"`
+	TotalCents int64           `json:"total_cents"` // despesas menos estornos/créditos
+	PaidCents  int64           `json:"paid_cents"`
+	Status     StatementStatus `json:"status"`
+	Items      []Transaction   `json:"items,omitempty"`
+	Payments   []Transaction   `json:"payments,omitempty"`
+}
+
+// StatementPayment descreve o pagamento de uma fatura a partir de outra conta
+type StatementPayment struct {
+	FromAccountID uuid.UUID
+	AmountCents   int64 // 0 = saldo em aberto da fatura
+	OccurredAt    time.Time
+}
+
+// cycle devolve as datas da fatura que fecha no mês "month" (primeiro dia do mês, UTC);
+// dias inexistentes caem no último dia do mês
+func (a *Account) cycle(month time.Time) (opens, closes, due time.Time) {
+	closes = monthDay(month, 0, a.ClosingDay)
+	opens = monthDay(month, -1, a.ClosingDay)
+	next := 0
+	if a.DueDay <= a.ClosingDay {
+		next = 1 // vence no mês seguinte ao fechamento
+	}
+	return opens, closes, monthDay(month, next, a.DueDay)
+}
+
+// statementMonth devolve o mês de fechamento da fatura que inclui "at"
+func (a *Account) statementMonth(at time.Time) time.Time {
+	at = at.UTC()
+	month := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if _, closes, _ := a.cycle(month); !at.Before(closes) {
+		month = month.AddDate(0, 1, 0)
+	}
+	return month
+}
+
+func parsePeriod(p string) (time.Time, error) {
+	t, err := time.Parse("2006-01", p)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: period must be YYYY-MM", ErrBadRequest)
+	}
+	return t, nil
+}
+
+// card carrega a conta e confere se é um cartão com ciclo de fatura configurado
+func (s *Service) card(ctx context.Context, id uuid.UUID) (*Account, error) {
+	a, err := s.repo.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Kind != CreditCard || a.ClosingDay == 0 {
+		return nil, fmt.Errorf("%w: account is not a credit card with closing_day and due_day", ErrBadRequest)
+	}
+	return a, nil
+}
+
+// Statements lista as faturas do cartão de "from" a "to" (YYYY-MM, meses de fechamento),
+// sem os lançamentos. Vazios assumem os últimos 12 meses até a fatura aberta.
+func (s *Service) Statements(ctx context.Context, cardID uuid.UUID, from, to string) ([]Statement, error) {
+	a, err := s.card(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	last := a.statementMonth(time.Now())
+	if to != "" {
+		if last, err = parsePeriod(to); err != nil {
+			return nil, err
+		}
+	}
+	first := last.AddDate(0, -11, 0)
+	if from != "" {
+		if first, err = parsePeriod(from); err != nil {
+			return nil, err
+		}
+	}
+	if last.Before(first) || first.AddDate(0, maxStatements, 0).Before(last.AddDate(0, 1, 0)) {
+		return nil, fmt.Errorf("%w: from..to must cover 1 to %d statements", ErrBadRequest, maxStatements)
+	}
+	return s.statements(ctx, a, first, last, false)
+}
+
+// Statement devolve a fatura do período com lançamentos e pagamentos
+func (s *Service) Statement(ctx context.Context, cardID uuid.UUID, period string) (*Statement, error) {
+	a, err := s.card(ctx, cardID)
+	if err != nil {
+		return nil, err
+	}
+	month, err := parsePeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	sts, err := s.statements(ctx, a, month, month, true)
+	if err != nil {
+		return nil, err
+	}
+	return &sts[0], nil
+}
+
+func (s *Service) statements(ctx context.Context, a *Account, first, last time.Time, items bool) ([]Statement, error) {
+	from, _, _ := a.cycle(first)
+	_, to, _ := a.cycle(last)
+	txs, err := s.repo.ListByPeriod(ctx, from, to.Add(-time.Microsecond), TxFilter{AccountID: &a.ID})
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.StatementPayments(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	var out []Statement
+	index := map[string]int{}
+	for m := first; !m.After(last); m = m.AddDate(0, 1, 0) {
+		st := Statement{AccountID: a.ID, Period: m.Format("2006-01"), Currency: a.Currency}
+		st.OpensAt, st.ClosesAt, st.DueAt = a.cycle(m)
+		index[st.Period] = len(out)
+		out = append(out, st)
+	}
+	for _, t := range txs {
+		i, ok := index[a.statementMonth(t.OccurredAt).Format("2006-01")]
+		if !ok || t.Type == Transfer {
+			continue
+		}
+		st := &out[i]
+		if t.Type == Expense {
+			st.TotalCents += t.AmountCents
+		} else {
+			st.TotalCents -= t.AmountCents
+		}
+		if items {
+			st.Items = append(st.Items, t)
+		}
+	}
+	for _, t := range payments {
+		if i, ok := index[t.Statement]; ok {
+			out[i].PaidCents += t.AmountCents
+			if items {
+				out[i].Payments = append(out[i].Payments, t)
+			}
+		}
+	}
+	for i := range out {
+		st := &out[i]
+		switch {
+		case now.Before(st.ClosesAt):
+			st.Status = StatementOpen
+		case st.PaidCents >= st.TotalCents:
+			st.Status = StatementPaid
+		case !now.Before(st.DueAt.AddDate(0, 0, 1)):
+			st.Status = StatementOverdue
+		default:
+			st.Status = StatementClosed
+		}
+	}
+	return out, nil
+}
+
+// PayStatement registra o pagamento da fatura como transferência da conta de origem para o
+// cartão, marcada com o período da fatura
+func (s *Service) PayStatement(ctx context.Context, cardID uuid.UUID, period string, in StatementPayment) (*TransferLegs, error) {
+	st, err := s.Statement(ctx, cardID, period)
+	if err != nil {
+		return nil, err
+	}
+	amount := in.AmountCents
+	if amount == 0 {
+		amount = st.TotalCents - st.PaidCents
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: statement has nothing to pay", ErrBadRequest)
+	}
+	return s.createTransfer(ctx, TransferInput{
+		FromAccountID: in.FromAccountID,
+		ToAccountID:   cardID,
+		AmountCents:   amount,
+		Description:   "Pagamento da fatura " + st.Period,
+		OccurredAt:    in.OccurredAt,
+	}, st.Period)
+}
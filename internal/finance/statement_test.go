@@ -0,0 +1,81 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_Statements(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	if _, err := s.CreateAccount(ctx, AccountInput{Name: "Cartão", Kind: CreditCard}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("card without cycle: %v", err)
+	}
+	card, err := s.CreateAccount(ctx, AccountInput{Name: "Cartão", Kind: CreditCard, ClosingDay: 5, DueDay: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bank, err := s.CreateAccount(ctx, AccountInput{Name: "Banco", Kind: Checking})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero, kind := 0, CreditCard
+	if _, err := s.UpdateAccount(ctx, card.ID, AccountPatch{ClosingDay: &zero, DueDay: &zero}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("patch removing the card cycle: %v", err)
+	}
+	if _, err := s.UpdateAccount(ctx, bank.ID, AccountPatch{Kind: &kind}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("patch to credit card without cycle: %v", err)
+	}
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 15, 0, 0, 0, time.UTC) }
+	for _, in := range []TxInput{
+		{Type: Expense, Category: "Mercado", AmountCents: 30000, OccurredAt: day(2, 5)},
+		{Type: Expense, Category: "Mercado", AmountCents: 20000, OccurredAt: day(3, 4)},
+		{Type: Expense, Category: "Lazer", AmountCents: 9000, OccurredAt: day(3, 5)}, // dia do fechamento: próxima fatura
+		{Type: Income, Category: "Estorno", AmountCents: 4000, OccurredAt: day(3, 20)},
+	} {
+		in.AccountID = &card.ID
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	st, err := s.Statement(ctx, card.ID, "2025-03")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.TotalCents != 50000 || len(st.Items) != 2 || !st.DueAt.Equal(time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)) ||
+		st.Status != StatementOverdue {
+		t.Errorf("march statement: %+v", st)
+	}
+	if _, err := s.PayStatement(ctx, card.ID, "2025-03", StatementPayment{FromAccountID: bank.ID, AmountCents: 10000, OccurredAt: day(3, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.PayStatement(ctx, card.ID, "2025-03", StatementPayment{FromAccountID: bank.ID, OccurredAt: day(3, 11)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.PayStatement(ctx, card.ID, "2025-03", StatementPayment{FromAccountID: bank.ID}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("paying a paid statement: %v", err)
+	}
+
+	sts, err := s.Statements(ctx, card.ID, "2025-03", "2025-04")
+	if err != nil || len(sts) != 2 {
+		t.Fatalf("statements: %+v %v", sts, err)
+	}
+	if sts[0].PaidCents != 50000 || sts[0].Status != StatementPaid || sts[0].Items != nil {
+		t.Errorf("paid statement: %+v", sts[0])
+	}
+	if sts[1].TotalCents != 5000 || sts[1].Status != StatementOverdue {
+		t.Errorf("april statement: %+v", sts[1])
+	}
+	if b, _ := s.Balance(ctx, bank.ID, day(12, 31)); b.BalanceCents != -50000 {
+		t.Errorf("bank balance: %+v", b)
+	}
+
+	// vencimento antes do fechamento cai no mês seguinte
+	late := Account{ClosingDay: 25, DueDay: 5}
+	if _, _, due := late.cycle(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); due.Month() != time.February {
+		t.Errorf("due: %v", due)
+	}
+}
@@ -0,0 +1,219 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxSummaryBuckets limita o tamanho da série de um resumo por período
+const maxSummaryBuckets = 731
+
+// Granularity é o tamanho dos intervalos da série; os nomes são os campos de date_trunc
+type Granularity string
+
+const (
+	GranularityDay     Granularity = "day"
+	GranularityWeek    Granularity = "week" // semanas ISO, começando na segunda-feira
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+	GranularityYear    Granularity = "year"
+)
+
+func (g Granularity) valid() bool {
+	switch g {
+	case GranularityDay, GranularityWeek, GranularityMonth, GranularityQuarter, GranularityYear:
+		return true
+	}
+	return false
+}
+
+// truncate devolve o início do intervalo que contém t (em UTC), como date_trunc
+func (g Granularity) truncate(t time.Time) time.Time {
+	t = t.UTC()
+	y, m, d := t.Date()
+	switch g {
+	case GranularityDay:
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	case GranularityWeek:
+		return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+	case GranularityQuarter:
+		return time.Date(y, (m-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+	case GranularityYear:
+		return time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// next devolve o início do intervalo seguinte a "start"
+func (g Granularity) next(start time.Time) time.Time {
+	switch g {
+	case GranularityDay:
+		return start.AddDate(0, 0, 1)
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityQuarter:
+		return start.AddDate(0, 3, 0)
+	case GranularityYear:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// SummaryBucket totaliza um intervalo da série
+type SummaryBucket struct {
+	Start   time.Time `json:"start"`
+	Income  int64     `json:"income_cents"`
+	Expense int64     `json:"expense_cents"`
+	Net     int64     `json:"net_cents"`
+	CountTx int       `json:"count_transactions"`
+}
+
+// RangeSummary é o resumo de um período arbitrário com a série de receitas e despesas por
+// intervalo; intervalos sem transações aparecem zerados
+type RangeSummary struct {
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Granularity Granularity     `json:"granularity"`
+	Income      int64           `json:"income_cents"`
+	Expense     int64           `json:"expense_cents"`
+	Net         int64           `json:"net_cents"`
+	Currency    string          `json:"currency"` // moeda base da conversão
+	CountTx     int             `json:"count_transactions"`
+	Buckets     []SummaryBucket `json:"buckets"`
+	Categories  []CategoryTotal `json:"categories"` // receitas e despesas por categoria
+}
+
+type SummaryRepository interface {
+	// RangeSummary agrupa as transações de from a to (inclusive, sem transferências) pelos
+	// intervalos de g, convertidas para "base", e devolve só os intervalos com transações e
+	// o detalhamento por categoria; ErrMissingRate se faltar cotação
+	RangeSummary(ctx context.Context, from, to time.Time, g Granularity, f TxFilter, base string) (*RangeSummary, error)
+}
+
+// RangeSummary totaliza o período em uma série de intervalos de tamanho g (vazio = mês),
+// convertendo cada transação para "base" (vazio = moeda base)
+func (s *Service) RangeSummary(ctx context.Context, from, to time.Time, g Granularity, f TxFilter, base string) (*RangeSummary, error) {
+	from, to = from.UTC(), to.UTC()
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", ErrBadRequest)
+	}
+	if g == "" {
+		g = GranularityMonth
+	}
+	if !g.valid() {
+		return nil, fmt.Errorf("%w: granularity must be day, week, month, quarter or year", ErrBadRequest)
+	}
+	var starts []time.Time
+	for b := g.truncate(from); !b.After(to); b = g.next(b) {
+		if len(starts) == maxSummaryBuckets {
+			return nil, fmt.Errorf("%w: range has more than %d %s buckets", ErrBadRequest, maxSummaryBuckets, g)
+		}
+		starts = append(starts, b)
+	}
+	base, err := s.resolveBase(base)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.normalizeTags(); err != nil {
+		return nil, err
+	}
+	rs, err := s.repo.RangeSummary(ctx, from, to, g, f, base)
+	if err != nil {
+		return nil, err
+	}
+	found := map[time.Time]SummaryBucket{}
+	for _, b := range rs.Buckets {
+		found[g.truncate(b.Start)] = b
+	}
+	rs.From, rs.To, rs.Granularity, rs.Currency = from, to, g, base
+	rs.Buckets = make([]SummaryBucket, len(starts))
+	rs.Income, rs.Expense, rs.CountTx = 0, 0, 0
+	for i, start := range starts {
+		b := found[start]
+		b.Start, b.Net = start, b.Income-b.Expense
+		rs.Buckets[i] = b
+		rs.Income += b.Income
+		rs.Expense += b.Expense
+		rs.CountTx += b.CountTx
+	}
+	rs.Net = rs.Income - rs.Expense
+	rs.Categories = finishCategories(rs.Categories, rs.Income, rs.Expense)
+	if err := s.categoryPaths(ctx, rs.Categories); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// categoryPaths preenche o caminho na hierarquia das categorias gerenciadas
+func (s *Service) categoryPaths(ctx context.Context, totals []CategoryTotal) error {
+	cats, err := s.loadCategories(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range totals {
+		if c, ok := cats.byKey[categoryKey(totals[i].Category)]; ok {
+			totals[i].Path = c.Path
+		}
+	}
+	return nil
+}
+
+// GenerateYearlyReport cria um relatório textual do ano, com a série mensal e o
+// detalhamento por categoria
+func (s *Service) GenerateYearlyReport(ctx context.Context, year int, base string) (string, error) {
+	sum, err := s.YearlySummary(ctx, year, base)
+	if err != nil {
+		return "", err
+	}
+	cur := sum.Currency
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `========================================
+RELATÓRIO FINANCEIRO ANUAL - %d
+========================================
+
+Moeda: %s
+Total de Transações: %d
+
+RESUMO FINANCEIRO:
+------------------------------------------
+Receitas:       %s
+Despesas:       %s
+------------------------------------------
+Saldo Final:    %s
+------------------------------------------
+
+`, year, cur, sum.CountTx, formatMoney(sum.Income, cur), formatMoney(sum.Expense, cur), formatMoney(sum.Net, cur))
+
+	b.WriteString("MÊS A MÊS:\n")
+	b.WriteString("------------------------------------------\n")
+	fmt.Fprintf(&b, "%-10s %14s %14s %14s\n", "Mês", "Receitas", "Despesas", "Saldo")
+	for _, m := range sum.Buckets {
+		fmt.Fprintf(&b, "%-10s %14s %14s %14s\n", monthNames[m.Start.Month()],
+			formatMoney(m.Income, cur), formatMoney(m.Expense, cur), formatMoney(m.Net, cur))
+	}
+	b.WriteString("------------------------------------------\n\n")
+
+	b.WriteString(categoryTable("RECEITAS POR CATEGORIA", Income, sum.Categories, cur))
+	b.WriteString(categoryTable("DESPESAS POR CATEGORIA", Expense, sum.Categories, cur))
+
+	status := "POSITIVO ✓"
+	if sum.Net < 0 {
+		status = "NEGATIVO ✗"
+	} else if sum.Net == 0 {
+		status = "NEUTRO"
+	}
+	fmt.Fprintf(&b, "Status: %s\n", status)
+	b.WriteString("========================================\n")
+	return b.String(), nil
+}
+
+// YearlySummary é o resumo do ano civil com a série mensal
+func (s *Service) YearlySummary(ctx context.Context, year int, base string) (*RangeSummary, error) {
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.RangeSummary(ctx, from, from.AddDate(1, 0, 0).Add(-time.Microsecond), GranularityMonth, TxFilter{}, base)
+}
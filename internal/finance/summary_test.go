@@ -0,0 +1,73 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGranularity_Truncate(t *testing.T) {
+	at := time.Date(2025, 8, 14, 15, 30, 0, 0, time.UTC) // quinta-feira
+	for g, want := range map[Granularity]string{
+		GranularityDay:     "2025-08-14",
+		GranularityWeek:    "2025-08-11",
+		GranularityMonth:   "2025-08-01",
+		GranularityQuarter: "2025-07-01",
+		GranularityYear:    "2025-01-01",
+	} {
+		if got := g.truncate(at).Format("2006-01-02"); got != want {
+			t.Errorf("%s: got %s, want %s", g, got, want)
+		}
+	}
+}
+
+func TestService_RangeSummary(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	for _, in := range []TxInput{
+		{Type: Income, Category: "Salário", AmountCents: 500000, OccurredAt: time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{Type: Expense, Category: "Mercado", AmountCents: 80000, OccurredAt: time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)},
+		{Type: Expense, Category: "Mercado", AmountCents: 20000, OccurredAt: time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)},
+		{Type: Expense, Category: "Viagem", AmountCents: 300000, OccurredAt: time.Date(2025, 11, 30, 12, 0, 0, 0, time.UTC)},
+	} {
+		if _, err := s.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from, to := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	rs, err := s.RangeSummary(ctx, from, to, "", TxFilter{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.Granularity != GranularityMonth || len(rs.Buckets) != 3 || rs.Income != 500000 || rs.Expense != 100000 {
+		t.Fatalf("unexpected summary: %+v", rs)
+	}
+	if b := rs.Buckets[1]; b.CountTx != 0 || !b.Start.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("empty month: %+v", b)
+	}
+	if b := rs.Buckets[0]; b.Net != 420000 || b.CountTx != 2 {
+		t.Errorf("january: %+v", b)
+	}
+
+	q, err := s.RangeSummary(ctx, from, to.AddDate(0, 9, 0), GranularityQuarter, TxFilter{Type: Expense}, "")
+	if err != nil || len(q.Buckets) != 4 || q.Buckets[0].Expense != 100000 || q.Buckets[3].Expense != 300000 {
+		t.Errorf("quarters: %+v %v", q, err)
+	}
+	if _, err := s.RangeSummary(ctx, from, from.AddDate(3, 0, 0), GranularityDay, TxFilter{}, ""); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("too many buckets: %v", err)
+	}
+	if _, err := s.RangeSummary(ctx, from, to, "hour", TxFilter{}, ""); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("bad granularity: %v", err)
+	}
+
+	report, err := s.GenerateYearlyReport(ctx, 2025, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(report, "ANUAL - 2025") || !strings.Contains(report, "Novembro") || !strings.Contains(report, "Viagem") {
+		t.Errorf("unexpected report:\n%s", report)
+	}
+}
@@ -0,0 +1,132 @@
+package finance
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxTags   = 20 // por transação
+	maxTagLen = 50
+)
+
+// TagMatch define como o filtro combina várias tags
+type TagMatch string
+
+const (
+	TagsAny TagMatch = "any" // a transação tem ao menos uma das tags
+	TagsAll TagMatch = "all" // a transação tem todas as tags
+)
+
+// TagTotal soma as receitas e despesas marcadas com uma tag; transações com várias tags
+// entram no total de cada uma
+type TagTotal struct {
+	Tag          string `json:"tag"`
+	IncomeCents  int64  `json:"income_cents"`
+	ExpenseCents int64  `json:"expense_cents"`
+	NetCents     int64  `json:"net_cents"`
+	Count        int    `json:"count"`
+}
+
+type TagSummary struct {
+	From     time.Time  `json:"from"`
+	To       time.Time  `json:"to"`
+	Currency string     `json:"currency"` // moeda base da conversão
+	Tags     []TagTotal `json:"tags"`
+}
+
+type TagRepository interface {
+	// TagTotals agrupa por tag as transações do período (sem transferências), convertidas
+	// para "base"; ErrMissingRate se faltar cotação
+	TagTotals(ctx context.Context, from, to time.Time, f TxFilter, base string) ([]TagTotal, error)
+}
+
+// normalizeTags apara, põe em minúsculas, remove duplicatas e ordena as tags
+func normalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || utf8.RuneCountInString(t) > maxTagLen || strings.Contains(t, ",") {
+			return nil, fmt.Errorf("%w: tags must have 1 to %d characters and no commas", ErrBadRequest, maxTagLen)
+		}
+		out = append(out, t)
+	}
+	slices.Sort(out)
+	out = slices.Compact(out)
+	if len(out) > maxTags {
+		return nil, fmt.Errorf("%w: at most %d tags per transaction", ErrBadRequest, maxTags)
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// normalizeTags prepara o filtro por tags para os repositórios
+func (f *TxFilter) normalizeTags() error {
+	switch f.TagMatch {
+	case "", TagsAny, TagsAll:
+	default:
+		return fmt.Errorf("%w: tag match must be any or all", ErrBadRequest)
+	}
+	tags, err := normalizeTags(f.Tags)
+	if err != nil {
+		return err
+	}
+	f.Tags = tags
+	return nil
+}
+
+// matchTags aplica o filtro por tags do TxFilter
+func (f TxFilter) matchTags(tags []string) bool {
+	if len(f.Tags) == 0 {
+		return true
+	}
+	for _, want := range f.Tags {
+		has := slices.Contains(tags, want)
+		if has && f.TagMatch != TagsAll {
+			return true
+		}
+		if !has && f.TagMatch == TagsAll {
+			return false
+		}
+	}
+	return f.TagMatch == TagsAll
+}
+
+// TagSummary totaliza as receitas e despesas por tag no período, na moeda "base" (vazio =
+// moeda base), das maiores despesas para as menores
+func (s *Service) TagSummary(ctx context.Context, from, to time.Time, f TxFilter, base string) (*TagSummary, error) {
+	if to.Before(from) {
+		return nil, ErrBadRequest
+	}
+	base, err := s.resolveBase(base)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.normalizeTags(); err != nil {
+		return nil, err
+	}
+	totals, err := s.repo.TagTotals(ctx, from.UTC(), to.UTC(), f, base)
+	if err != nil {
+		return nil, err
+	}
+	if totals == nil {
+		totals = []TagTotal{}
+	}
+	for i := range totals {
+		totals[i].NetCents = totals[i].IncomeCents - totals[i].ExpenseCents
+	}
+	slices.SortFunc(totals, func(a, b TagTotal) int {
+		if a.ExpenseCents != b.ExpenseCents {
+			return cmp.Compare(b.ExpenseCents, a.ExpenseCents)
+		}
+		return strings.Compare(a.Tag, b.Tag)
+	})
+	return &TagSummary{From: from.UTC(), To: to.UTC(), Currency: base, Tags: totals}, nil
+}
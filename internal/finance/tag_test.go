@@ -0,0 +1,59 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_Tags(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ctx := context.Background()
+	at := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	var hotel *Transaction
+	for _, in := range []TxInput{
+		{Type: Expense, Category: "Viagem", AmountCents: 80000, Tags: []string{" Trip-Lisbon", "reimbursable", "trip-lisbon"}},
+		{Type: Expense, Category: "Restaurante", AmountCents: 12000, Tags: []string{"trip-lisbon"}},
+		{Type: Income, Category: "Reembolso", AmountCents: 80000, Tags: []string{"reimbursable"}},
+		{Type: Expense, Category: "Mercado", AmountCents: 5000},
+	} {
+		in.OccurredAt = at
+		tx, err := s.Create(ctx, in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hotel == nil {
+			hotel = tx
+		}
+	}
+	if len(hotel.Tags) != 2 || hotel.Tags[0] != "reimbursable" || hotel.Tags[1] != "trip-lisbon" {
+		t.Errorf("tags not normalized: %v", hotel.Tags)
+	}
+	if _, err := s.Create(ctx, TxInput{Type: Expense, Category: "x", AmountCents: 1, Tags: []string{"a,b"}}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("invalid tag: %v", err)
+	}
+
+	from, to := at.AddDate(0, 0, -1), at.AddDate(0, 0, 1)
+	anyOf, _ := s.ListByPeriod(ctx, from, to, TxFilter{Tags: []string{"trip-lisbon", "Reimbursable"}})
+	allOf, _ := s.ListByPeriod(ctx, from, to, TxFilter{Tags: []string{"trip-lisbon", "reimbursable"}, TagMatch: TagsAll})
+	if len(anyOf) != 3 || len(allOf) != 1 || allOf[0].ID != hotel.ID {
+		t.Errorf("tag filter: any=%d all=%d", len(anyOf), len(allOf))
+	}
+
+	sum, err := s.TagSummary(ctx, from, to, TxFilter{}, "")
+	if err != nil || len(sum.Tags) != 2 {
+		t.Fatalf("summary: %+v %v", sum, err)
+	}
+	if trip := sum.Tags[0]; trip.Tag != "trip-lisbon" || trip.ExpenseCents != 92000 || trip.Count != 2 {
+		t.Errorf("trip: %+v", trip)
+	}
+	if r := sum.Tags[1]; r.IncomeCents != 80000 || r.ExpenseCents != 80000 || r.NetCents != 0 {
+		t.Errorf("reimbursable: %+v", r)
+	}
+
+	none := []string{}
+	if tx, err := s.Update(ctx, hotel.ID, TxPatch{Tags: &none}); err != nil || tx.Tags != nil {
+		t.Errorf("clearing tags: %+v %v", tx, err)
+	}
+}
@@ -0,0 +1,88 @@
+package finance
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTokenTTL é a validade dos tokens emitidos no login
+const DefaultTokenTTL = 24 * time.Hour
+
+// WithAuthSecret define a chave HMAC dos tokens. Sem ela o Service gera uma chave
+// aleatória, e os tokens deixam de valer quando o processo reinicia.
+func WithAuthSecret(secret []byte) Option {
+	return func(s *Service) {
+		if len(secret) > 0 {
+			s.authSecret = secret
+		}
+	}
+}
+
+// WithTokenTTL define a validade dos tokens de sessão
+func WithTokenTTL(d time.Duration) Option {
+	return func(s *Service) {
+		if d > 0 {
+			s.tokenTTL = d
+		}
+	}
+}
+
+func randomSecret() []byte {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return b
+}
+
+// Tokens são JWT HS256 com as claims sub (id do usuário), iat e exp
+type tokenClaims struct {
+	Sub string `json:"sub"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+var (
+	b64       = base64.RawURLEncoding
+	jwtHeader = b64.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+)
+
+func (s *Service) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.authSecret)
+	mac.Write([]byte(payload))
+	return b64.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) signToken(user uuid.UUID, exp time.Time) string {
+	claims, _ := json.Marshal(tokenClaims{Sub: user.String(), Iat: time.Now().Unix(), Exp: exp.Unix()})
+	payload := jwtHeader + "." + b64.EncodeToString(claims)
+	return payload + "." + s.sign(payload)
+}
+
+func (s *Service) verifyToken(token string, now time.Time) (uuid.UUID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return uuid.Nil, ErrUnauthorized
+	}
+	if !hmac.Equal([]byte(s.sign(parts[0]+"."+parts[1])), []byte(parts[2])) {
+		return uuid.Nil, ErrUnauthorized
+	}
+	raw, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return uuid.Nil, ErrUnauthorized
+	}
+	var c tokenClaims
+	if err := json.Unmarshal(raw, &c); err != nil || now.Unix() >= c.Exp {
+		return uuid.Nil, ErrUnauthorized
+	}
+	id, err := uuid.Parse(c.Sub)
+	if err != nil {
+		return uuid.Nil, ErrUnauthorized
+	}
+	return id, nil
+}
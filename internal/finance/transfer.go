@@ -0,0 +1,94 @@
+package finance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TransferCategory é a categoria atribuída às pernas de transferência
+const TransferCategory = "transfer"
+
+type TransferInput struct {
+	FromAccountID uuid.UUID
+	ToAccountID   uuid.UUID
+	AmountCents   int64
+	Description   string
+	OccurredAt    time.Time
+}
+
+// TransferLegs agrupa as duas pernas (saída e entrada) de uma transferência
+type TransferLegs struct {
+	ID  uuid.UUID   `json:"id"`
+	Out Transaction `json:"out"`
+	In  Transaction `json:"in"`
+}
+
+// CreateTransfer move dinheiro entre duas contas da mesma moeda. As pernas não entram nos
+// totais de receita/despesa do resumo mensal, mas afetam o saldo de cada conta.
+func (s *Service) CreateTransfer(ctx context.Context, in TransferInput) (*TransferLegs, error) {
+	return s.createTransfer(ctx, in, "")
+}
+
+// createTransfer grava a transferência; statement identifica a fatura paga quando o destino
+// é um cartão (ver PayStatement)
+func (s *Service) createTransfer(ctx context.Context, in TransferInput, statement string) (*TransferLegs, error) {
+	if in.FromAccountID == in.ToAccountID || in.AmountCents <= 0 {
+		return nil, ErrBadRequest
+	}
+	from, err := s.repo.GetAccount(ctx, in.FromAccountID)
+	if err != nil {
+		return nil, accountRefErr(err)
+	}
+	to, err := s.repo.GetAccount(ctx, in.ToAccountID)
+	if err != nil {
+		return nil, accountRefErr(err)
+	}
+	if from.Currency != to.Currency {
+		return nil, fmt.Errorf("%w: accounts have different currencies", ErrBadRequest)
+	}
+
+	now := time.Now().UTC()
+	occurred := in.OccurredAt.UTC()
+	if in.OccurredAt.IsZero() {
+		occurred = now
+	}
+	if occurred.After(now.Add(s.maxFuture)) {
+		return nil, fmt.Errorf("%w: occurred_at too far in the future", ErrBadRequest)
+	}
+
+	tid := uuid.New()
+	leg := func(acc uuid.UUID, dir TransferDirection) Transaction {
+		return Transaction{
+			ID:          uuid.New(),
+			LedgerID:    ledgerScope(ctx),
+			Type:        Transfer,
+			Category:    TransferCategory,
+			AmountCents: in.AmountCents,
+			Currency:    from.Currency,
+			OccurredAt:  occurred,
+			Description: strings.TrimSpace(in.Description),
+			AccountID:   &acc,
+			TransferID:  &tid,
+			Direction:   dir,
+			Statement:   statement,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	t := &TransferLegs{ID: tid, Out: leg(from.ID, TransferOut), In: leg(to.ID, TransferIn)}
+	if err := s.repo.CreateTransfer(ctx, &t.Out, &t.In); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func accountRefErr(err error) error {
+	if err == ErrNotFound {
+		return fmt.Errorf("%w: unknown account", ErrBadRequest)
+	}
+	return err
+}
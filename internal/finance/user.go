@@ -0,0 +1,132 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrUnauthorized indica credenciais ou token inválidos
+var ErrUnauthorized = errors.New("unauthorized")
+
+// MinPasswordLen é o tamanho mínimo aceito para senhas
+const MinPasswordLen = 8
+
+type User struct {
+	ID           uuid.UUID `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"` // bcrypt
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type UserRepository interface {
+	// CreateUser falha com ErrConflict se o e-mail já estiver cadastrado. Cria também o
+	// livro pessoal do usuário (mesmo ID), com ele como owner.
+	CreateUser(ctx context.Context, u *User) error
+	GetUser(ctx context.Context, id uuid.UUID) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+}
+
+type ctxKey int
+
+const (
+	userCtxKey ctxKey = iota
+	ledgerCtxKey
+)
+
+// WithUser devolve um contexto autenticado como o usuário id
+func WithUser(ctx context.Context, id uuid.UUID) context.Context {
+	return context.WithValue(ctx, userCtxKey, id)
+}
+
+// UserFromContext devolve o usuário autenticado, se houver
+func UserFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(userCtxKey).(uuid.UUID)
+	return id, ok
+}
+
+func normalizeEmail(e string) (string, bool) {
+	e = strings.ToLower(strings.TrimSpace(e))
+	addr, err := mail.ParseAddress(e)
+	if err != nil || addr.Address != e {
+		return "", false
+	}
+	return e, true
+}
+
+// Register cria um usuário com a senha protegida por bcrypt
+func (s *Service) Register(ctx context.Context, email, password string) (*User, error) {
+	email, ok := normalizeEmail(email)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid email", ErrBadRequest)
+	}
+	if len(password) < MinPasswordLen {
+		return nil, fmt.Errorf("%w: password must have at least %d characters", ErrBadRequest, MinPasswordLen)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return nil, fmt.Errorf("%w: password too long", ErrBadRequest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	u := &User{ID: uuid.New(), Email: email, PasswordHash: string(hash), CreatedAt: time.Now().UTC()}
+	if err := s.repo.CreateUser(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// Login confere as credenciais e emite um token de sessão assinado
+func (s *Service) Login(ctx context.Context, email, password string) (token string, exp time.Time, err error) {
+	email, _ = normalizeEmail(email)
+	u, err := s.repo.GetUserByEmail(ctx, email)
+	if errors.Is(err, ErrNotFound) {
+		// compara mesmo assim para não revelar, pelo tempo de resposta, quais e-mails existem
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return "", time.Time{}, ErrUnauthorized
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return "", time.Time{}, ErrUnauthorized
+	}
+	exp = time.Now().UTC().Add(s.tokenTTL)
+	return s.signToken(u.ID, exp), exp, nil
+}
+
+var dummyHash = sync.OnceValue(func() []byte {
+	h, _ := bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	return h
+})
+
+// Authenticate valida um token de sessão (JWT do login) ou de API (prefixo ft_)
+func (s *Service) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	now := time.Now().UTC()
+	if strings.HasPrefix(token, APITokenPrefix) {
+		return s.authenticateAPIToken(ctx, token, now)
+	}
+	id, err := s.verifyToken(token, now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetUser(ctx, id); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrUnauthorized
+		}
+		return nil, err
+	}
+	return &Principal{UserID: id}, nil
+}
+
+func (s *Service) GetUser(ctx context.Context, id uuid.UUID) (*User, error) {
+	return s.repo.GetUser(ctx, id)
+}
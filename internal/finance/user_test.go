@@ -0,0 +1,91 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_RegisterLogin(t *testing.T) {
+	s := NewService(NewMemoryRepo())
+	ctx := context.Background()
+
+	u, err := s.Register(ctx, " Ana@Example.com ", "segredo-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Email != "ana@example.com" || u.PasswordHash == "segredo-123" {
+		t.Errorf("unexpected user: %+v", u)
+	}
+	if _, err := s.Register(ctx, "ana@example.com", "outra-senha"); !errors.Is(err, ErrConflict) {
+		t.Errorf("duplicate email: %v", err)
+	}
+	if _, err := s.Register(ctx, "bob@example.com", "curta"); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("short password: %v", err)
+	}
+
+	if _, _, err := s.Login(ctx, "ana@example.com", "errada!!"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("wrong password: %v", err)
+	}
+	token, exp, err := s.Login(ctx, "ANA@example.com", "segredo-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exp.After(time.Now()) {
+		t.Errorf("exp = %v", exp)
+	}
+	p, err := s.Authenticate(ctx, token)
+	if err != nil || p.UserID != u.ID || !p.IsSession() {
+		t.Fatalf("authenticate: %+v %v", p, err)
+	}
+	if _, err := s.Authenticate(ctx, token+"x"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("tampered token: %v", err)
+	}
+	if _, err := s.verifyToken(token, exp.Add(time.Second)); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expired token: %v", err)
+	}
+	other := NewService(NewMemoryRepo(), WithAuthSecret([]byte("outra-chave")))
+	if _, err := other.verifyToken(token, time.Now()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("token signed with another key: %v", err)
+	}
+}
+
+func TestRepo_OwnerIsolation(t *testing.T) {
+	s := NewService(NewMemoryRepo(), WithCategoryAutoCreate(true))
+	ana, _ := s.Register(context.Background(), "ana@example.com", "segredo-123")
+	bob, _ := s.Register(context.Background(), "bob@example.com", "segredo-456")
+	actx := WithUser(context.Background(), ana.ID)
+	bctx := WithUser(context.Background(), bob.ID)
+
+	acc, err := s.CreateAccount(actx, AccountInput{Name: "Conta", Kind: Checking})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := s.Create(actx, TxInput{Type: Expense, Category: "food", AmountCents: 100, AccountID: &acc.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Get(bctx, tx.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bob read ana's transaction: %v", err)
+	}
+	if err := s.Delete(bctx, tx.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bob deleted ana's transaction: %v", err)
+	}
+	if _, err := s.GetAccount(bctx, acc.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("bob read ana's account: %v", err)
+	}
+	if _, err := s.Create(bctx, TxInput{Type: Expense, Category: "food", AmountCents: 1, AccountID: &acc.ID}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("bob used ana's account: %v", err)
+	}
+	now := time.Now()
+	list, _ := s.ListByPeriod(bctx, now.Add(-time.Hour), now.Add(time.Hour), TxFilter{})
+	sum, _ := s.MonthlySummary(bctx, now.Year(), int(now.Month()), TxFilter{}, "")
+	if len(list) != 0 || sum.CountTx != 0 {
+		t.Errorf("bob sees %d transactions, summary count %d", len(list), sum.CountTx)
+	}
+	if _, err := s.Get(actx, tx.ID); err != nil {
+		t.Errorf("ana lost her transaction: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postAccountReq struct {
+	Name                string `json:"name"`
+	Kind                string `json:"kind"`     // checking | savings | credit_card | cash
+	Currency            string `json:"currency"` // opcional, padrão BRL
+	OpeningBalanceCents int64  `json:"opening_balance_cents"`
+	ClosingDay          int    `json:"closing_day"` // obrigatório para credit_card
+	DueDay              int    `json:"due_day"`     // obrigatório para credit_card
+}
+
+type patchAccountReq struct {
+	Name                *string `json:"name"`
+	Kind                *string `json:"kind"`
+	Currency            *string `json:"currency"`
+	OpeningBalanceCents *int64  `json:"opening_balance_cents"`
+	ClosingDay          *int    `json:"closing_day"`
+	DueDay              *int    `json:"due_day"`
+}
+
+func postAccount(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postAccountReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		a, err := svc.CreateAccount(r.Context(), finance.AccountInput{
+			Name:                in.Name,
+			Kind:                finance.AccountKind(in.Kind),
+			Currency:            in.Currency,
+			OpeningBalanceCents: in.OpeningBalanceCents,
+			ClosingDay:          in.ClosingDay,
+			DueDay:              in.DueDay,
+		})
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, a)
+	}
+}
+
+func listAccounts(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := svc.ListAccounts(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func getAccount(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		a, err := svc.GetAccount(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, a)
+	}
+}
+
+func patchAccount(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in patchAccountReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		p := finance.AccountPatch{
+			Name:                in.Name,
+			Currency:            in.Currency,
+			OpeningBalanceCents: in.OpeningBalanceCents,
+			ClosingDay:          in.ClosingDay,
+			DueDay:              in.DueDay,
+		}
+		if in.Kind != nil {
+			k := finance.AccountKind(*in.Kind)
+			p.Kind = &k
+		}
+		a, err := svc.UpdateAccount(r.Context(), id, p)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, a)
+	}
+}
+
+func deleteAccount(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.DeleteAccount(r.Context(), id); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// accountBalance aceita ?at= em RFC3339 ou YYYY-MM-DD (fim do dia); padrão é agora
+func accountBalance(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		at := time.Now()
+		if v := r.URL.Query().Get("at"); v != "" {
+			at, err = parseAt(v)
+			if err != nil {
+				serr(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		b, err := svc.Balance(r.Context(), id, at)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, b)
+	}
+}
+
+// parseAt interpreta um instante de corte; datas sem hora valem até 23:59:59 do dia
+func parseAt(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, errString("'at' must be RFC3339 or YYYY-MM-DD")
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
@@ -0,0 +1,147 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+// authenticator protege as rotas do NewMux. Aceita "Authorization: Bearer" com o token
+// de sessão do login ou com um token de API (que só acessa rotas do seu escopo).
+type authenticator struct {
+	svc *finance.Service
+}
+
+func (a authenticator) authenticate(w http.ResponseWriter, r *http.Request) (*finance.Principal, bool) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="finance-tracker"`)
+		serr(w, errString("missing bearer token"), http.StatusUnauthorized)
+		return nil, false
+	}
+	p, err := a.svc.Authenticate(r.Context(), strings.TrimSpace(token))
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="finance-tracker", error="invalid_token"`)
+		serr(w, err, errStatus(err))
+		return nil, false
+	}
+	return p, true
+}
+
+// any aceita qualquer credencial válida
+func (a authenticator) any(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, found := a.authenticate(w, r)
+		if !found {
+			return
+		}
+		next(w, r.WithContext(finance.WithUser(r.Context(), p.UserID)))
+	}
+}
+
+// session exige login (tokens de API não criam tokens nem administram livros)
+func (a authenticator) session(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, found := a.authenticate(w, r)
+		if !found {
+			return
+		}
+		if !p.IsSession() {
+			serr(w, errString("this endpoint requires a login session"), http.StatusForbidden)
+			return
+		}
+		next(w, r.WithContext(finance.WithUser(r.Context(), p.UserID)))
+	}
+}
+
+// ledgerHeader escolhe o livro da requisição; sem ele vale o livro pessoal do usuário
+const ledgerHeader = "X-Ledger-ID"
+
+// ledger exige o escopo informado (para tokens de API), resolve o livro da requisição e
+// exige pelo menos o papel min do usuário nele
+func (a authenticator) ledger(min finance.Role, scope finance.Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, found := a.authenticate(w, r)
+		if !found {
+			return
+		}
+		if !p.HasScope(scope) {
+			serr(w, errString("token lacks scope "+string(scope)), http.StatusForbidden)
+			return
+		}
+		ctx := finance.WithUser(r.Context(), p.UserID)
+		ledger := p.UserID
+		if v := r.Header.Get(ledgerHeader); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				serr(w, errString("header "+ledgerHeader+" must be a UUID"), http.StatusBadRequest)
+				return
+			}
+			ledger = id
+		}
+		if _, err := a.svc.Authorize(ctx, ledger, min); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		next(w, r.WithContext(finance.WithLedger(ctx, ledger)))
+	}
+}
+
+type credentialsReq struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func register(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in credentialsReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		u, err := svc.Register(r.Context(), in.Email, in.Password)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, u)
+	}
+}
+
+type loginResp struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func login(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in credentialsReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		token, exp, err := svc.Login(r.Context(), in.Email, in.Password)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, loginResp{Token: token, TokenType: "Bearer", ExpiresAt: exp})
+	}
+}
+
+func me(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, _ := finance.UserFromContext(r.Context())
+		u, err := svc.GetUser(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, u)
+	}
+}
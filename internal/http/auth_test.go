@@ -0,0 +1,120 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+const txBody = `{"type":"expense","category":"market","amount_cents":1000}`
+
+func TestAuth_RequiresValidToken(t *testing.T) {
+	svc := finance.NewService(finance.NewMemoryRepo(), finance.WithCategoryAutoCreate(true))
+	mux := NewMux(svc)
+	signUp(t, svc, "ana@example.com")
+
+	for name, tok := range map[string]string{"missing": "", "invalid": "not-a-jwt", "api token": "ft_invalido"} {
+		rec := call(mux, http.MethodGet, "/transactions?from=2025-01-01&to=2025-01-31", tok, "")
+		if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s token: %d %s", name, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := call(mux, http.MethodGet, "/health", "", ""); rec.Code != http.StatusOK {
+		t.Errorf("health: %d", rec.Code)
+	}
+}
+
+func TestAuth_ViewerCannotWrite(t *testing.T) {
+	svc := finance.NewService(finance.NewMemoryRepo(), finance.WithCategoryAutoCreate(true))
+	mux := NewMux(svc)
+	ana := signUp(t, svc, "ana@example.com")
+	bob := signUp(t, svc, "bob@example.com")
+
+	rec := call(mux, http.MethodPost, "/ledgers", ana, `{"name":"Casa"}`)
+	var casa finance.Ledger
+	if err := json.Unmarshal(rec.Body.Bytes(), &casa); rec.Code != http.StatusCreated || err != nil {
+		t.Fatalf("create ledger: %d %s", rec.Code, rec.Body.String())
+	}
+	rec = call(mux, http.MethodPost, "/ledgers/"+casa.ID.String()+"/members", ana, `{"email":"bob@example.com","role":"viewer"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("add member: %d %s", rec.Code, rec.Body.String())
+	}
+
+	inLedger := func(method, path, token, body string) int {
+		req := newReq(method, path, token, body)
+		req.Header.Set(ledgerHeader, casa.ID.String())
+		return serve(mux, req).Code
+	}
+	if code := inLedger(http.MethodPost, "/transactions", bob, txBody); code != http.StatusForbidden {
+		t.Errorf("viewer create: %d", code)
+	}
+	if code := inLedger(http.MethodPost, "/transactions", ana, txBody); code != http.StatusCreated {
+		t.Errorf("owner create: %d", code)
+	}
+	if code := inLedger(http.MethodGet, "/transactions?from=2000-01-01&to=2100-01-01", bob, ""); code != http.StatusOK {
+		t.Errorf("viewer list: %d", code)
+	}
+	// no livro pessoal, bob é owner
+	if rec := call(mux, http.MethodPost, "/transactions", bob, txBody); rec.Code != http.StatusCreated {
+		t.Errorf("personal ledger create: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAuth_TokenScopes(t *testing.T) {
+	svc := finance.NewService(finance.NewMemoryRepo(), finance.WithCategoryAutoCreate(true))
+	mux := NewMux(svc)
+	p, err := svc.Authenticate(context.Background(), signUp(t, svc, "ana@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, secret, err := svc.CreateAPIToken(finance.WithUser(context.Background(), p.UserID), finance.APITokenInput{
+		Name:      "relatórios",
+		Scopes:    []finance.Scope{finance.ScopeReportsRead},
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, w := range []struct{ method, path, body string }{
+		{http.MethodPost, "/transactions", txBody},
+		{http.MethodPost, "/transactions:batch", "[" + txBody + "]"},
+		{http.MethodPost, "/accounts", `{"name":"Conta","kind":"checking"}`},
+		{http.MethodPost, "/rates", `[{"from":"USD","to":"BRL","date":"2025-01-01","rate":5}]`},
+		{http.MethodGet, "/transactions?from=2025-01-01&to=2025-01-31", ""},
+		{http.MethodPost, "/tokens", `{"name":"x","scopes":["transactions:write"]}`},
+	} {
+		if rec := call(mux, w.method, w.path, secret, w.body); rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: %d %s", w.method, w.path, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := call(mux, http.MethodGet, "/summary/monthly?year=2025&month=1", secret, ""); rec.Code != http.StatusOK {
+		t.Errorf("summary with reports:read: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestAuth_TransactionsIsolatedByUser(t *testing.T) {
+	svc := finance.NewService(finance.NewMemoryRepo(), finance.WithCategoryAutoCreate(true))
+	mux := NewMux(svc)
+	ana := signUp(t, svc, "ana@example.com")
+	bob := signUp(t, svc, "bob@example.com")
+
+	rec := call(mux, http.MethodPost, "/transactions", bob, txBody)
+	var tx finance.Transaction
+	if err := json.Unmarshal(rec.Body.Bytes(), &tx); rec.Code != http.StatusCreated || err != nil {
+		t.Fatalf("create: %d %s", rec.Code, rec.Body.String())
+	}
+	path := "/transactions/" + tx.ID.String()
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		if rec := call(mux, method, path, ana, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("%s by another user: %d %s", method, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := call(mux, http.MethodGet, path, bob, ""); rec.Code != http.StatusOK {
+		t.Errorf("get by owner: %d", rec.Code)
+	}
+}
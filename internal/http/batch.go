@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+// maxBatchBody limita o tamanho do corpo aceito em POST /transactions:batch
+const maxBatchBody = 16 << 20
+
+// postTransactionsBatch aceita um array JSON ou NDJSON (um objeto por linha) de itens
+// no formato de POST /transactions. Query param atomic=true|false (padrão true); fora do
+// modo atômico, itens com erro de formato também viram resultados com erro.
+func postTransactionsBatch(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		atomic := true
+		if v := r.URL.Query().Get("atomic"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				serr(w, errString("query param 'atomic' must be true or false"), http.StatusBadRequest)
+				return
+			}
+			atomic = b
+		}
+
+		reqs, err := decodeBatch(http.MaxBytesReader(w, r.Body, maxBatchBody))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if len(reqs) > finance.MaxBatchSize {
+			serr(w, errString(fmt.Sprintf("batch exceeds %d items", finance.MaxBatchSize)), http.StatusBadRequest)
+			return
+		}
+		// itens com erro de formato (ex: data inválida) ficam fora do lote; pos guarda a
+		// posição original de cada item enviado ao Service
+		ins := make([]finance.TxInput, 0, len(reqs))
+		pos := make([]int, 0, len(reqs))
+		var parseErrs []finance.BatchResult
+		for i, in := range reqs {
+			txIn, err := in.toInput()
+			if err != nil {
+				parseErrs = append(parseErrs, finance.BatchResult{Index: i, Error: err.Error()})
+				continue
+			}
+			ins = append(ins, txIn)
+			pos = append(pos, i)
+		}
+		if len(parseErrs) > 0 && atomic {
+			// erros de formato são reportados como os de validação
+			writeJSON(w, http.StatusBadRequest, finance.BatchOutcome{Atomic: atomic, Failed: len(parseErrs), Results: parseErrs})
+			return
+		}
+
+		res := &finance.BatchOutcome{Atomic: atomic}
+		if len(ins) > 0 || len(parseErrs) == 0 {
+			if res, err = svc.CreateBatch(r.Context(), ins, atomic); err != nil {
+				serr(w, err, errStatus(err))
+				return
+			}
+		}
+		if len(parseErrs) > 0 {
+			results := make([]finance.BatchResult, len(reqs))
+			for _, pe := range parseErrs {
+				results[pe.Index] = pe
+			}
+			for _, br := range res.Results {
+				br.Index = pos[br.Index]
+				results[br.Index] = br
+			}
+			res.Results = results
+			res.Failed += len(parseErrs)
+		}
+		switch {
+		case res.Failed == 0:
+			created(w, res)
+		case atomic:
+			writeJSON(w, http.StatusUnprocessableEntity, res)
+		default:
+			ok(w, res)
+		}
+	}
+}
+
+// decodeBatch detecta o formato pelo primeiro caractere significativo: '[' é array JSON,
+// qualquer outra coisa é tratada como sequência de objetos (NDJSON)
+func decodeBatch(body io.Reader) ([]postTxReq, error) {
+	br := bufio.NewReader(body)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return nil, errString("empty batch body")
+		}
+		if b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n' {
+			_, _ = br.ReadByte()
+			continue
+		}
+		break
+	}
+	dec := json.NewDecoder(br)
+	var out []postTxReq
+	if b, _ := br.Peek(1); b[0] == '[' {
+		if err := dec.Decode(&out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	for {
+		var in postTxReq
+		err := dec.Decode(&in)
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ndjson item %d: %w", len(out), err)
+		}
+		if len(out) >= finance.MaxBatchSize {
+			return nil, errString(fmt.Sprintf("batch exceeds %d items", finance.MaxBatchSize))
+		}
+		out = append(out, in)
+	}
+}
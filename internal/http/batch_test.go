@@ -0,0 +1,77 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+// signUp cadastra o usuário e devolve o token de sessão
+func signUp(t *testing.T, svc *finance.Service, email string) string {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := svc.Register(ctx, email, "segredo-123"); err != nil {
+		t.Fatal(err)
+	}
+	tok, _, err := svc.Login(ctx, email, "segredo-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func newReq(method, path, token, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func serve(mux http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func call(mux http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	return serve(mux, newReq(method, path, token, body))
+}
+
+func TestBatch_ParseErrors(t *testing.T) {
+	svc := finance.NewService(finance.NewMemoryRepo(), finance.WithCategoryAutoCreate(true))
+	mux := NewMux(svc)
+	tok := signUp(t, svc, "ana@example.com")
+	body := `[
+		{"type":"income","category":"salary","amount_cents":1000},
+		{"type":"expense","category":"rent","amount_cents":500,"occurred_at":"ontem"},
+		{"type":"expense","category":"","amount_cents":300}
+	]`
+
+	rec := call(mux, http.MethodPost, "/transactions:batch?atomic=true", tok, body)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("atomic: %d %s", rec.Code, rec.Body.String())
+	}
+
+	rec = call(mux, http.MethodPost, "/transactions:batch?atomic=false", tok, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("non-atomic: %d %s", rec.Code, rec.Body.String())
+	}
+	var out finance.BatchOutcome
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Created != 1 || out.Failed != 2 || len(out.Results) != 3 {
+		t.Fatalf("outcome: %+v", out)
+	}
+	for i, r := range out.Results {
+		if r.Index != i || (i == 0) != (r.Transaction != nil) || (i == 0) != (r.Error == "") {
+			t.Errorf("result %d: %+v", i, r)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postBudgetReq struct {
+	Category   string `json:"category"` // nome, apelido ou caminho
+	Month      string `json:"month"`    // opcional: YYYY-MM; vazio = todo mês
+	LimitCents int64  `json:"limit_cents"`
+}
+
+type patchBudgetReq struct {
+	Category   *string `json:"category"`
+	Month      *string `json:"month"` // "" torna o orçamento recorrente
+	LimitCents *int64  `json:"limit_cents"`
+}
+
+func postBudget(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postBudgetReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		b, err := svc.CreateBudget(r.Context(), finance.BudgetInput{
+			Category:   in.Category,
+			Month:      in.Month,
+			LimitCents: in.LimitCents,
+		})
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, b)
+	}
+}
+
+func listBudgets(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := svc.ListBudgets(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func getBudget(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		b, err := svc.GetBudget(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, b)
+	}
+}
+
+func patchBudget(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in patchBudgetReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		b, err := svc.UpdateBudget(r.Context(), id, finance.BudgetPatch{
+			Category:   in.Category,
+			Month:      in.Month,
+			LimitCents: in.LimitCents,
+		})
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, b)
+	}
+}
+
+func deleteBudget(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.DeleteBudget(r.Context(), id); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func budgetStatus(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		y, err := strconv.Atoi(r.URL.Query().Get("year"))
+		if err != nil {
+			serr(w, errString("query param 'year' is required"), http.StatusBadRequest)
+			return
+		}
+		m, err := strconv.Atoi(r.URL.Query().Get("month"))
+		if err != nil {
+			serr(w, errString("query param 'month' is required"), http.StatusBadRequest)
+			return
+		}
+		rep, err := svc.BudgetStatus(r.Context(), y, m)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, rep)
+	}
+}
@@ -0,0 +1,77 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type payStatementReq struct {
+	FromAccountID uuid.UUID `json:"from_account_id"`
+	AmountCents   int64     `json:"amount_cents"` // opcional: padrão é o saldo em aberto da fatura
+	OccurredAt    string    `json:"occurred_at"`  // opcional: RFC3339 ou YYYY-MM-DD
+}
+
+// listStatements aceita ?from=YYYY-MM&to=YYYY-MM (meses de fechamento)
+func listStatements(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		q := r.URL.Query()
+		items, err := svc.Statements(r.Context(), id, q.Get("from"), q.Get("to"))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func getStatement(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		st, err := svc.Statement(r.Context(), id, r.PathValue("period"))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, st)
+	}
+}
+
+func payStatement(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in payStatementReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		p := finance.StatementPayment{FromAccountID: in.FromAccountID, AmountCents: in.AmountCents}
+		if in.OccurredAt != "" {
+			if p.OccurredAt, err = parseOccurredAt(in.OccurredAt); err != nil {
+				serr(w, err, http.StatusBadRequest)
+				return
+			}
+		}
+		t, err := svc.PayStatement(r.Context(), id, r.PathValue("period"), p)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, t)
+	}
+}
@@ -0,0 +1,126 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postCategoryReq struct {
+	Name     string     `json:"name"`
+	ParentID *uuid.UUID `json:"parent_id"` // opcional
+	Aliases  []string   `json:"aliases"`
+	Color    string     `json:"color"` // opcional: #RRGGBB
+	Icon     string     `json:"icon"`
+}
+
+type patchCategoryReq struct {
+	Name     *string   `json:"name"`
+	ParentID *string   `json:"parent_id"` // "" move para a raiz
+	Aliases  *[]string `json:"aliases"`
+	Color    *string   `json:"color"`
+	Icon     *string   `json:"icon"`
+}
+
+func postCategory(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postCategoryReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		c, err := svc.CreateCategory(r.Context(), finance.CategoryInput{
+			Name:     in.Name,
+			ParentID: in.ParentID,
+			Aliases:  in.Aliases,
+			Color:    in.Color,
+			Icon:     in.Icon,
+		})
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, c)
+	}
+}
+
+func listCategories(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := svc.ListCategories(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func getCategory(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		c, err := svc.GetCategory(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, c)
+	}
+}
+
+func patchCategory(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in patchCategoryReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		p := finance.CategoryPatch{
+			Name:    in.Name,
+			Aliases: in.Aliases,
+			Color:   in.Color,
+			Icon:    in.Icon,
+		}
+		if in.ParentID != nil {
+			parent := uuid.Nil
+			if *in.ParentID != "" {
+				if parent, err = uuid.Parse(*in.ParentID); err != nil {
+					serr(w, errString("parent_id must be a UUID or empty"), http.StatusBadRequest)
+					return
+				}
+			}
+			p.ParentID = &parent
+		}
+		c, err := svc.UpdateCategory(r.Context(), id, p)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, c)
+	}
+}
+
+func deleteCategory(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.DeleteCategory(r.Context(), id); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
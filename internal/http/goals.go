@@ -0,0 +1,87 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postGoalReq struct {
+	Name        string     `json:"name"`
+	TargetCents int64      `json:"target_cents"`
+	TargetDate  string     `json:"target_date"` // YYYY-MM-DD
+	AccountID   *uuid.UUID `json:"account_id"`  // conta ou tag, não os dois
+	Tag         string     `json:"tag"`
+}
+
+func postGoal(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postGoalReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		date, err := time.Parse("2006-01-02", in.TargetDate)
+		if err != nil {
+			serr(w, errString("target_date must be YYYY-MM-DD"), http.StatusBadRequest)
+			return
+		}
+		g, err := svc.CreateGoal(r.Context(), finance.GoalInput{
+			Name:        in.Name,
+			TargetCents: in.TargetCents,
+			TargetDate:  date,
+			AccountID:   in.AccountID,
+			Tag:         in.Tag,
+		})
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, g)
+	}
+}
+
+func listGoals(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := svc.ListGoals(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func getGoal(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		g, err := svc.GetGoal(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, g)
+	}
+}
+
+func deleteGoal(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.DeleteGoal(r.Context(), id); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
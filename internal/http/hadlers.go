@@ -2,36 +2,146 @@ package httpapi
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/vinimax001/finance-tracker/internal/finance"
 	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
 )
 
 const s3BucketName = "finance-tracker-releases"
 
 func NewMux(svc *finance.Service) *http.ServeMux {
+	im := finance.NewImporter(svc)
+	auth := authenticator{svc}
+	// papel mínimo no livro e escopo exigido de tokens de API, por rota
+	viewer, editor := finance.RoleViewer, finance.RoleEditor
+	txRead, txWrite, reportsRead := finance.ScopeTransactionsRead, finance.ScopeTransactionsWrite, finance.ScopeReportsRead
 	m := http.NewServeMux()
 	m.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		ok(w, map[string]string{"status": "ok"})
 	})
-	m.HandleFunc("POST /transactions", postTransaction(svc))
-	m.HandleFunc("GET /transactions", listTransactions(svc))
-	m.HandleFunc("DELETE /transactions/{id}", deleteTransaction(svc))
-	m.HandleFunc("GET /summary/monthly", monthlySummary(svc))
-	m.HandleFunc("GET /reports/monthly", monthlyReport(svc))
+	m.HandleFunc("POST /auth/register", register(svc))
+	m.HandleFunc("POST /auth/login", login(svc))
+	m.HandleFunc("GET /auth/me", auth.any(me(svc)))
+	m.HandleFunc("POST /tokens", auth.session(postAPIToken(svc)))
+	m.HandleFunc("GET /tokens", auth.session(listAPITokens(svc)))
+	m.HandleFunc("DELETE /tokens/{id}", auth.session(revokeAPIToken(svc)))
+	m.HandleFunc("POST /ledgers", auth.session(postLedger(svc)))
+	m.HandleFunc("GET /ledgers", auth.session(listLedgers(svc)))
+	m.HandleFunc("GET /ledgers/{id}/members", auth.session(listMembers(svc)))
+	m.HandleFunc("POST /ledgers/{id}/members", auth.session(postMember(svc)))
+	m.HandleFunc("PATCH /ledgers/{id}/members/{user_id}", auth.session(patchMember(svc)))
+	m.HandleFunc("DELETE /ledgers/{id}/members/{user_id}", auth.session(deleteMember(svc)))
+	m.HandleFunc("POST /transactions", auth.ledger(editor, txWrite, postTransaction(svc)))
+	m.HandleFunc("POST /transactions:batch", auth.ledger(editor, txWrite, postTransactionsBatch(svc)))
+	m.HandleFunc("GET /transactions", auth.ledger(viewer, txRead, listTransactions(svc)))
+	m.HandleFunc("GET /transactions/{id}", auth.ledger(viewer, txRead, getTransaction(svc)))
+	m.HandleFunc("PATCH /transactions/{id}", auth.ledger(editor, txWrite, patchTransaction(svc)))
+	m.HandleFunc("DELETE /transactions/{id}", auth.ledger(editor, txWrite, deleteTransaction(svc)))
+	m.HandleFunc("POST /transfers", auth.ledger(editor, txWrite, postTransfer(svc)))
+	m.HandleFunc("GET /summary/monthly", auth.ledger(viewer, reportsRead, monthlySummary(svc)))
+	m.HandleFunc("GET /summary/tags", auth.ledger(viewer, reportsRead, tagSummary(svc)))
+	m.HandleFunc("GET /summary/range", auth.ledger(viewer, reportsRead, rangeSummary(svc)))
+	m.HandleFunc("GET /summary/compare", auth.ledger(viewer, reportsRead, compareSummary(svc)))
+	m.HandleFunc("GET /forecast", auth.ledger(viewer, reportsRead, forecast(svc)))
+	m.HandleFunc("POST /accounts", auth.ledger(editor, txWrite, postAccount(svc)))
+	m.HandleFunc("GET /accounts", auth.ledger(viewer, txRead, listAccounts(svc)))
+	m.HandleFunc("GET /accounts/{id}", auth.ledger(viewer, txRead, getAccount(svc)))
+	m.HandleFunc("PATCH /accounts/{id}", auth.ledger(editor, txWrite, patchAccount(svc)))
+	m.HandleFunc("DELETE /accounts/{id}", auth.ledger(editor, txWrite, deleteAccount(svc)))
+	m.HandleFunc("GET /accounts/{id}/balance", auth.ledger(viewer, txRead, accountBalance(svc)))
+	m.HandleFunc("GET /cards/{id}/statements", auth.ledger(viewer, txRead, listStatements(svc)))
+	m.HandleFunc("GET /cards/{id}/statements/{period}", auth.ledger(viewer, txRead, getStatement(svc)))
+	m.HandleFunc("POST /cards/{id}/statements/{period}/pay", auth.ledger(editor, txWrite, payStatement(svc)))
+	m.HandleFunc("POST /categories", auth.ledger(editor, txWrite, postCategory(svc)))
+	m.HandleFunc("GET /categories", auth.ledger(viewer, txRead, listCategories(svc)))
+	m.HandleFunc("GET /categories/{id}", auth.ledger(viewer, txRead, getCategory(svc)))
+	m.HandleFunc("PATCH /categories/{id}", auth.ledger(editor, txWrite, patchCategory(svc)))
+	m.HandleFunc("DELETE /categories/{id}", auth.ledger(editor, txWrite, deleteCategory(svc)))
+	m.HandleFunc("POST /budgets", auth.ledger(editor, txWrite, postBudget(svc)))
+	m.HandleFunc("GET /budgets", auth.ledger(viewer, txRead, listBudgets(svc)))
+	m.HandleFunc("GET /budgets/status", auth.ledger(viewer, reportsRead, budgetStatus(svc)))
+	m.HandleFunc("GET /budgets/{id}", auth.ledger(viewer, txRead, getBudget(svc)))
+	m.HandleFunc("PATCH /budgets/{id}", auth.ledger(editor, txWrite, patchBudget(svc)))
+	m.HandleFunc("DELETE /budgets/{id}", auth.ledger(editor, txWrite, deleteBudget(svc)))
+	m.HandleFunc("POST /goals", auth.ledger(editor, txWrite, postGoal(svc)))
+	m.HandleFunc("GET /goals", auth.ledger(viewer, txRead, listGoals(svc)))
+	m.HandleFunc("GET /goals/{id}", auth.ledger(viewer, txRead, getGoal(svc)))
+	m.HandleFunc("DELETE /goals/{id}", auth.ledger(editor, txWrite, deleteGoal(svc)))
+	m.HandleFunc("POST /recurring", auth.ledger(editor, txWrite, postRecurring(svc)))
+	m.HandleFunc("GET /recurring", auth.ledger(viewer, txRead, listRecurring(svc)))
+	m.HandleFunc("GET /recurring/upcoming", auth.ledger(viewer, txRead, upcomingRecurring(svc)))
+	m.HandleFunc("GET /recurring/{id}", auth.ledger(viewer, txRead, getRecurring(svc)))
+	m.HandleFunc("DELETE /recurring/{id}", auth.ledger(editor, txWrite, deleteRecurring(svc)))
+	m.HandleFunc("POST /recurring/{id}/skip", auth.ledger(editor, txWrite, skipRecurring(svc)))
+	m.HandleFunc("POST /installments", auth.ledger(editor, txWrite, postInstallment(svc)))
+	m.HandleFunc("GET /installments", auth.ledger(viewer, txRead, listInstallments(svc)))
+	m.HandleFunc("GET /installments/{id}", auth.ledger(viewer, txRead, getInstallment(svc)))
+	m.HandleFunc("DELETE /installments/{id}", auth.ledger(editor, txWrite, deleteInstallment(svc)))
+	m.HandleFunc("POST /installments/{id}/cancel", auth.ledger(editor, txWrite, cancelInstallment(svc)))
+	m.HandleFunc("POST /installments/{id}/prepay", auth.ledger(editor, txWrite, prepayInstallment(svc)))
+	m.HandleFunc("POST /imports/csv", auth.ledger(editor, txWrite, importCSV(im)))
+	m.HandleFunc("POST /imports/ofx", auth.ledger(editor, txWrite, importOFX(im)))
+	m.HandleFunc("POST /rates", auth.ledger(editor, txWrite, postRates(svc)))
+	m.HandleFunc("GET /rates", auth.ledger(viewer, txRead, listRates(svc)))
+	m.HandleFunc("GET /reports/monthly", auth.ledger(editor, reportsRead, monthlyReport(svc)))
+	m.HandleFunc("GET /reports/yearly", auth.ledger(editor, reportsRead, yearlyReport(svc)))
 	return m
 }
 
 type postTxReq struct {
-	Type        string `json:"type"` // income | expense
-	Category    string `json:"category"`
-	AmountCents int64  `json:"amount_cents"` // centavos
-	Description string `json:"description"`  // opcional
+	Type        string     `json:"type"` // income | expense
+	Category    string     `json:"category"`
+	AmountCents int64      `json:"amount_cents"` // centavos
+	Currency    string     `json:"currency"`     // opcional: ISO 4217, padrão da conta ou BRL
+	Description string     `json:"description"`  // opcional
+	OccurredAt  string     `json:"occurred_at"`  // opcional: RFC3339 ou YYYY-MM-DD
+	AccountID   *uuid.UUID `json:"account_id"`   // opcional
+	Tags        []string   `json:"tags"`         // opcional
+	// opcional: rateio entre categorias; a soma das linhas deve ser amount_cents
+	Splits []finance.Split `json:"splits"`
+}
+
+// toInput converte o corpo da requisição para finance.TxInput
+func (in postTxReq) toInput() (finance.TxInput, error) {
+	txIn := finance.TxInput{
+		Type:        finance.TxType(in.Type),
+		Category:    in.Category,
+		AmountCents: in.AmountCents,
+		Currency:    in.Currency,
+		Description: in.Description,
+		AccountID:   in.AccountID,
+		Tags:        in.Tags,
+		Splits:      in.Splits,
+	}
+	if in.OccurredAt != "" {
+		at, err := parseOccurredAt(in.OccurredAt)
+		if err != nil {
+			return txIn, err
+		}
+		txIn.OccurredAt = at
+	}
+	return txIn, nil
+}
+
+// parseOccurredAt aceita RFC3339 completo ou apenas a data (YYYY-MM-DD, meia-noite UTC)
+func parseOccurredAt(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, errString("occurred_at must be RFC3339 or YYYY-MM-DD")
+	}
+	return t, nil
 }
 
 func postTransaction(svc *finance.Service) http.HandlerFunc {
@@ -41,19 +151,41 @@ func postTransaction(svc *finance.Service) http.HandlerFunc {
 			serr(w, err, http.StatusBadRequest)
 			return
 		}
-		tx, err := svc.Create(r.Context(), finance.TxType(in.Type), in.Category, in.AmountCents, in.Description)
+		txIn, err := in.toInput()
 		if err != nil {
-			status := http.StatusInternalServerError
-			if err == finance.ErrBadRequest {
-				status = http.StatusBadRequest
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			rec, replayed, err := svc.CreateIdempotent(r.Context(), key, requestHash(in), txIn)
+			if err != nil {
+				serr(w, err, errStatus(err))
+				return
 			}
-			serr(w, err, status)
+			if replayed {
+				w.Header().Set("Idempotent-Replayed", "true")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(rec.Response)
+			return
+		}
+		tx, err := svc.Create(r.Context(), txIn)
+		if err != nil {
+			serr(w, err, errStatus(err))
 			return
 		}
 		created(w, tx)
 	}
 }
 
+// requestHash identifica o corpo já decodificado, ignorando espaços e ordem das chaves
+func requestHash(in postTxReq) string {
+	b, _ := json.Marshal(in)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
 func listTransactions(svc *finance.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		fromStr := r.URL.Query().Get("from")
@@ -72,19 +204,155 @@ func listTransactions(svc *finance.Service) http.HandlerFunc {
 			serr(w, err, http.StatusBadRequest)
 			return
 		}
-		items, err := svc.ListByPeriod(r.Context(), from, to.Add(23*time.Hour+59*time.Minute+59*time.Second))
+		f, err := parseTxFilter(r)
 		if err != nil {
-			status := http.StatusInternalServerError
-			if err == finance.ErrBadRequest {
-				status = http.StatusBadRequest
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		q := finance.TxQuery{
+			From:   from,
+			To:     to.Add(23*time.Hour + 59*time.Minute + 59*time.Second),
+			Filter: f,
+			Sort:   finance.TxSort(r.URL.Query().Get("sort")),
+			Cursor: r.URL.Query().Get("cursor"),
+		}
+		switch r.URL.Query().Get("order") {
+		case "", "asc":
+		case "desc":
+			q.Desc = true
+		default:
+			serr(w, errString("query param 'order' must be 'asc' or 'desc'"), http.StatusBadRequest)
+			return
+		}
+		if v := r.URL.Query().Get("limit"); v != "" {
+			if q.Limit, err = strconv.Atoi(v); err != nil {
+				serr(w, err, http.StatusBadRequest)
+				return
 			}
-			serr(w, err, status)
+		}
+		page, err := svc.List(r.Context(), q)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, page)
+	}
+}
+
+func getTransaction(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		tx, err := svc.Get(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
 			return
 		}
-		ok(w, items)
+		ok(w, tx)
 	}
 }
 
+// patchTxReq usa ponteiros para distinguir campo ausente de valor zero
+type patchTxReq struct {
+	Type        *string    `json:"type"`
+	Category    *string    `json:"category"`
+	AmountCents *int64     `json:"amount_cents"`
+	Description *string    `json:"description"`
+	OccurredAt  *string    `json:"occurred_at"`
+	AccountID   *uuid.UUID `json:"account_id"`
+	Currency    *string    `json:"currency"`
+	Tags        *[]string  `json:"tags"` // substitui todas as tags
+	// substitui o rateio; [] remove
+	Splits *[]finance.Split `json:"splits"`
+}
+
+func patchTransaction(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in patchTxReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		p := finance.TxPatch{
+			Category:    in.Category,
+			AmountCents: in.AmountCents,
+			Description: in.Description,
+			AccountID:   in.AccountID,
+			Currency:    in.Currency,
+			Tags:        in.Tags,
+			Splits:      in.Splits,
+		}
+		if in.Type != nil {
+			typ := finance.TxType(*in.Type)
+			p.Type = &typ
+		}
+		if in.OccurredAt != nil {
+			at, err := parseOccurredAt(*in.OccurredAt)
+			if err != nil {
+				serr(w, err, http.StatusBadRequest)
+				return
+			}
+			p.OccurredAt = &at
+		}
+		tx, err := svc.Update(r.Context(), id, p)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, tx)
+	}
+}
+
+// parseTxFilter lê os filtros opcionais comuns a listagens e resumos
+func parseTxFilter(r *http.Request) (finance.TxFilter, error) {
+	var f finance.TxFilter
+	if v := r.URL.Query().Get("account_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return f, err
+		}
+		f.AccountID = &id
+	}
+	if v := r.URL.Query().Get("type"); v != "" {
+		switch t := finance.TxType(v); t {
+		case finance.Income, finance.Expense, finance.Transfer:
+			f.Type = t
+		default:
+			return f, errString("query param 'type' must be income, expense or transfer")
+		}
+	}
+	f.Category = strings.TrimSpace(r.URL.Query().Get("category"))
+	f.Description = strings.TrimSpace(r.URL.Query().Get("q"))
+	// ?tag=a&tag=b ou ?tag=a,b; tag_match=all exige todas
+	for _, v := range r.URL.Query()["tag"] {
+		f.Tags = append(f.Tags, strings.Split(v, ",")...)
+	}
+	switch m := finance.TagMatch(r.URL.Query().Get("tag_match")); m {
+	case "", finance.TagsAny, finance.TagsAll:
+		f.TagMatch = m
+	default:
+		return f, errString("query param 'tag_match' must be 'any' or 'all'")
+	}
+	for param, dst := range map[string]**int64{"min_amount": &f.MinAmount, "max_amount": &f.MaxAmount} {
+		if v := r.URL.Query().Get(param); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return f, errString("query param '" + param + "' must be an integer (cents)")
+			}
+			*dst = &n
+		}
+	}
+	return f, nil
+}
+
 func deleteTransaction(svc *finance.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		idStr := r.PathValue("id")
@@ -123,19 +391,59 @@ func monthlySummary(svc *finance.Service) http.HandlerFunc {
 			serr(w, err, http.StatusBadRequest)
 			return
 		}
-		sum, err := svc.MonthlySummary(r.Context(), y, m)
+		f, err := parseTxFilter(r)
 		if err != nil {
-			status := http.StatusInternalServerError
-			if err == finance.ErrBadRequest {
-				status = http.StatusBadRequest
-			}
-			serr(w, err, status)
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		sum, err := svc.MonthlySummary(r.Context(), y, m, f, r.URL.Query().Get("currency"))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, sum)
+	}
+}
+
+// tagSummary aceita from/to (YYYY-MM-DD, inclusive), currency e os filtros de transações
+func tagSummary(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := parseDateRange(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		f, err := parseTxFilter(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		sum, err := svc.TagSummary(r.Context(), from, to, f, r.URL.Query().Get("currency"))
+		if err != nil {
+			serr(w, err, errStatus(err))
 			return
 		}
 		ok(w, sum)
 	}
 }
 
+// parseDateRange lê from/to (YYYY-MM-DD, obrigatórios); to vai até o fim do dia
+func parseDateRange(r *http.Request) (from, to time.Time, err error) {
+	return parseDates(r, "from", "to")
+}
+
+// parseDates lê um período com os nomes de parâmetros informados
+func parseDates(r *http.Request, fromKey, toKey string) (from, to time.Time, err error) {
+	msg := fmt.Sprintf("query params '%s' and '%s' are required (YYYY-MM-DD)", fromKey, toKey)
+	if from, err = time.Parse("2006-01-02", r.URL.Query().Get(fromKey)); err != nil {
+		return from, to, errString(msg)
+	}
+	if to, err = time.Parse("2006-01-02", r.URL.Query().Get(toKey)); err != nil {
+		return from, to, errString(msg)
+	}
+	return from, to.Add(24*time.Hour - time.Second), nil
+}
+
 func monthlyReport(svc *finance.Service) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		yearStr := r.URL.Query().Get("year")
@@ -156,18 +464,15 @@ func monthlyReport(svc *finance.Service) http.HandlerFunc {
 		}
 
 		// Gerar relatório textual
-		reportText, err := svc.GenerateMonthlyReport(r.Context(), y, m)
+		base := r.URL.Query().Get("currency")
+		reportText, err := svc.GenerateMonthlyReport(r.Context(), y, m, base)
 		if err != nil {
-			status := http.StatusInternalServerError
-			if err == finance.ErrBadRequest {
-				status = http.StatusBadRequest
-			}
-			serr(w, err, status)
+			serr(w, err, errStatus(err))
 			return
 		}
 
-		// Nome do arquivo: report-YYYY-MM.txt
-		fileName := fmt.Sprintf("reports/report-%04d-%02d.txt", y, m)
+		// Nome do arquivo: reports/<livro>/report-YYYY-MM.txt
+		fileName := fmt.Sprintf("reports/%s/report-%04d-%02d.txt", finance.LedgerFromContext(r.Context()), y, m)
 
 		// Upload para S3 em background com contexto independente
 		go func() {
@@ -184,7 +489,7 @@ func monthlyReport(svc *finance.Service) http.HandlerFunc {
 		}()
 
 		// Buscar summary para resposta JSON
-		sum, err := svc.MonthlySummary(r.Context(), y, m)
+		sum, err := svc.MonthlySummary(r.Context(), y, m, finance.TxFilter{}, base)
 		if err != nil {
 			status := http.StatusInternalServerError
 			if err == finance.ErrBadRequest {
@@ -215,6 +520,26 @@ type stringErr string
 func (e stringErr) Error() string { return string(e) }
 func errString(s string) error    { return stringErr(s) }
 
+// errStatus traduz os erros de domínio para o status HTTP correspondente
+func errStatus(err error) int {
+	switch {
+	case errors.Is(err, finance.ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, finance.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, finance.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, finance.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, finance.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, finance.ErrMissingRate), errors.Is(err, finance.ErrIdempotencyMismatch):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func ok(w http.ResponseWriter, v any)      { writeJSON(w, http.StatusOK, v) }
 func created(w http.ResponseWriter, v any) { writeJSON(w, http.StatusCreated, v) }
 func serr(w http.ResponseWriter, err error, status int) {
@@ -224,4 +549,4 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(v)
-}
\ No newline at end of file
+}
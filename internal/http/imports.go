@@ -0,0 +1,102 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+// maxImportBody limita o tamanho do upload de extratos
+const maxImportBody = 16 << 20
+
+// parseDryRun lê ?dry_run= (padrão false)
+func parseDryRun(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("dry_run")
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errString("query param 'dry_run' must be true or false")
+	}
+	return b, nil
+}
+
+// importCSV recebe multipart/form-data com os campos "file" (o CSV) e "mapping" (JSON
+// com finance.CSVMapping). Com ?dry_run=true devolve a prévia sem gravar.
+func importCSV(im *finance.Importer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		dryRun, err := parseDryRun(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportBody)
+		if err := r.ParseMultipartForm(maxImportBody); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var m finance.CSVMapping
+		if err := json.Unmarshal([]byte(r.FormValue("mapping")), &m); err != nil {
+			serr(w, errString("form field 'mapping' must be a JSON object"), http.StatusBadRequest)
+			return
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			serr(w, errString("form field 'file' is required"), http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+
+		res, err := im.ImportCSV(r.Context(), f, m, dryRun)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		if dryRun {
+			ok(w, res)
+			return
+		}
+		created(w, res)
+	}
+}
+
+// importOFX recebe o arquivo OFX no corpo (ou no campo multipart "file"), com
+// ?account_id= e ?default_category= opcionais. Lançamentos já importados (mesmo FITID
+// na mesma conta) são ignorados e contados em "skipped".
+func importOFX(im *finance.Importer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opt := finance.OFXOptions{DefaultCategory: r.URL.Query().Get("default_category")}
+		if v := r.URL.Query().Get("account_id"); v != "" {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				serr(w, errString("query param 'account_id' must be a UUID"), http.StatusBadRequest)
+				return
+			}
+			opt.AccountID = &id
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxImportBody)
+		body := io.Reader(r.Body)
+		if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "multipart/form-data" {
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				serr(w, errString("form field 'file' is required"), http.StatusBadRequest)
+				return
+			}
+			defer f.Close()
+			body = f
+		}
+
+		res, err := im.ImportOFX(r.Context(), body, opt)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, res)
+	}
+}
@@ -0,0 +1,96 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postInstallmentReq struct {
+	postTxReq           // amount_cents é o total e occurred_at a data da primeira parcela
+	Installments int    `json:"installments"`
+	Remainder    string `json:"remainder"` // opcional: first | last, padrão first
+}
+
+func postInstallment(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postInstallmentReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		txIn, err := in.toInput()
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		p, err := svc.CreateInstallment(r.Context(), finance.InstallmentInput{
+			TxInput:   txIn,
+			Count:     in.Installments,
+			Remainder: finance.InstallmentRemainder(in.Remainder),
+		})
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, p)
+	}
+}
+
+func listInstallments(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := svc.ListInstallments(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func getInstallment(svc *finance.Service) http.HandlerFunc {
+	return installmentAction(svc.GetInstallment)
+}
+
+func cancelInstallment(svc *finance.Service) http.HandlerFunc {
+	return installmentAction(svc.CancelInstallment)
+}
+
+func prepayInstallment(svc *finance.Service) http.HandlerFunc {
+	return installmentAction(svc.PrepayInstallment)
+}
+
+// installmentAction responde com a compra devolvida por fn para o {id} da rota
+func installmentAction(fn func(ctx context.Context, id uuid.UUID) (*finance.Installment, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		p, err := fn(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, p)
+	}
+}
+
+func deleteInstallment(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.DeleteInstallment(r.Context(), id); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
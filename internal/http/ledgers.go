@@ -0,0 +1,128 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postLedgerReq struct {
+	Name string `json:"name"`
+}
+
+func postLedger(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postLedgerReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		l, err := svc.CreateLedger(r.Context(), in.Name)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, l)
+	}
+}
+
+func listLedgers(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ls, err := svc.ListLedgers(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, ls)
+	}
+}
+
+func listMembers(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		ms, err := svc.ListMembers(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, ms)
+	}
+}
+
+type memberReq struct {
+	Email string `json:"email"` // apenas no convite
+	Role  string `json:"role"`  // owner | editor | viewer
+}
+
+// postMember convida um usuário cadastrado para o livro (exige owner)
+func postMember(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in memberReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		m, err := svc.AddMember(r.Context(), id, in.Email, finance.Role(in.Role))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, m)
+	}
+}
+
+// memberPath lê {id} (livro) e {user_id} da rota
+func memberPath(r *http.Request) (ledger, user uuid.UUID, err error) {
+	if ledger, err = uuid.Parse(r.PathValue("id")); err != nil {
+		return
+	}
+	user, err = uuid.Parse(r.PathValue("user_id"))
+	return
+}
+
+func patchMember(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ledger, user, err := memberPath(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in memberReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		m, err := svc.UpdateMember(r.Context(), ledger, user, finance.Role(in.Role))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, m)
+	}
+}
+
+func deleteMember(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ledger, user, err := memberPath(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.RemoveMember(r.Context(), ledger, user); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type rateReq struct {
+	From string  `json:"from"` // ISO 4217
+	To   string  `json:"to"`   // ISO 4217
+	Date string  `json:"date"` // YYYY-MM-DD
+	Rate float64 `json:"rate"` // 1 From = Rate To
+}
+
+// postRates importa (upsert) um lote de cotações diárias
+func postRates(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in []rateReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		rates := make([]finance.ExchangeRate, 0, len(in))
+		for _, v := range in {
+			d, err := time.Parse("2006-01-02", v.Date)
+			if err != nil {
+				serr(w, errString("rate date must be YYYY-MM-DD"), http.StatusBadRequest)
+				return
+			}
+			rates = append(rates, finance.ExchangeRate{From: v.From, To: v.To, Date: d, Rate: v.Rate})
+		}
+		if err := svc.ImportRates(r.Context(), rates); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, map[string]int{"imported": len(rates)})
+	}
+}
+
+func listRates(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from := strings.ToUpper(r.URL.Query().Get("from"))
+		to := strings.ToUpper(r.URL.Query().Get("to"))
+		items, err := svc.ListRates(r.Context(), from, to)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
@@ -0,0 +1,156 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postRecurringReq struct {
+	postTxReq
+	Frequency  string `json:"frequency"`    // weekly | monthly | yearly
+	Interval   int    `json:"interval"`     // opcional, padrão 1
+	DayOfMonth int    `json:"day_of_month"` // opcional (mensal), padrão o dia de start_at
+	StartAt    string `json:"start_at"`     // opcional: RFC3339 ou YYYY-MM-DD, padrão agora
+	Until      string `json:"until"`        // opcional: RFC3339 ou YYYY-MM-DD (inclusive)
+	Count      int    `json:"count"`        // opcional: número máximo de ocorrências
+}
+
+type skipReq struct {
+	Date string `json:"date"` // YYYY-MM-DD
+}
+
+func postRecurring(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postRecurringReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		txIn, err := in.toInput()
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		ri := finance.RecurringInput{
+			TxInput:    txIn,
+			Frequency:  finance.Frequency(in.Frequency),
+			Interval:   in.Interval,
+			DayOfMonth: in.DayOfMonth,
+			Count:      in.Count,
+		}
+		if in.StartAt != "" {
+			if ri.StartAt, err = parseOccurredAt(in.StartAt); err != nil {
+				serr(w, errString("start_at must be RFC3339 or YYYY-MM-DD"), http.StatusBadRequest)
+				return
+			}
+		}
+		if in.Until != "" {
+			until, err := parseAt(in.Until)
+			if err != nil {
+				serr(w, errString("until must be RFC3339 or YYYY-MM-DD"), http.StatusBadRequest)
+				return
+			}
+			ri.Until = &until
+		}
+		rule, err := svc.CreateRecurring(r.Context(), ri)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, rule)
+	}
+}
+
+func listRecurring(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := svc.ListRecurring(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func getRecurring(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		rule, err := svc.GetRecurring(r.Context(), id)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, rule)
+	}
+}
+
+func deleteRecurring(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.DeleteRecurring(r.Context(), id); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// upcomingRecurring aceita ?days= (1 a 366, padrão 30) a partir de agora
+func upcomingRecurring(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		days := 30
+		if v := r.URL.Query().Get("days"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > 366 {
+				serr(w, errString("'days' must be between 1 and 366"), http.StatusBadRequest)
+				return
+			}
+			days = n
+		}
+		items, err := svc.UpcomingOccurrences(r.Context(), time.Now().AddDate(0, 0, days))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, items)
+	}
+}
+
+func skipRecurring(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var in skipReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		date, err := time.Parse("2006-01-02", in.Date)
+		if err != nil {
+			serr(w, errString("date must be YYYY-MM-DD"), http.StatusBadRequest)
+			return
+		}
+		rule, err := svc.SkipOccurrence(r.Context(), id, date)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, rule)
+	}
+}
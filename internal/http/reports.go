@@ -0,0 +1,133 @@
+package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+// rangeSummary aceita from/to (YYYY-MM-DD, inclusive), granularity
+// (day|week|month|quarter|year, padrão month), currency e os filtros de transações
+func rangeSummary(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := parseDateRange(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		f, err := parseTxFilter(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		g := finance.Granularity(r.URL.Query().Get("granularity"))
+		sum, err := svc.RangeSummary(r.Context(), from, to, g, f, r.URL.Query().Get("currency"))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, sum)
+	}
+}
+
+// compareSummary compara o período from/to com compare_from/compare_to (YYYY-MM-DD,
+// inclusive); aceita currency e os filtros de transações
+func compareSummary(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		from, to, err := parseDateRange(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		prevFrom, prevTo, err := parseDates(r, "compare_from", "compare_to")
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		f, err := parseTxFilter(r)
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		c, err := svc.Compare(r.Context(), finance.DateRange{From: from, To: to},
+			finance.DateRange{From: prevFrom, To: prevTo}, f, r.URL.Query().Get("currency"))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, c)
+	}
+}
+
+// forecast aceita months (1 a 24, padrão 6), history (meses de histórico para as médias,
+// padrão 6) e currency
+func forecast(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		months, history := 6, 0
+		for name, dst := range map[string]*int{"months": &months, "history": &history} {
+			if v := r.URL.Query().Get(name); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					serr(w, errString("query param '"+name+"' must be an integer"), http.StatusBadRequest)
+					return
+				}
+				*dst = n
+			}
+		}
+		fc, err := svc.Forecast(r.Context(), time.Now(), months, history, r.URL.Query().Get("currency"))
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, fc)
+	}
+}
+
+// yearlyReport é a variante anual de monthlyReport: devolve o resumo com a série mensal e
+// o relatório textual, que também é enviado ao S3
+func yearlyReport(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		y, err := strconv.Atoi(r.URL.Query().Get("year"))
+		if err != nil {
+			serr(w, errString("query param 'year' is required"), http.StatusBadRequest)
+			return
+		}
+		base := r.URL.Query().Get("currency")
+		reportText, err := svc.GenerateYearlyReport(r.Context(), y, base)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		sum, err := svc.YearlySummary(r.Context(), y, base)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+
+		fileName := fmt.Sprintf("reports/%s/report-%04d.txt", finance.LedgerFromContext(r.Context()), y)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := finance.UploadReportToS3(ctx, s3BucketName, fileName, reportText); err != nil {
+				fmt.Printf("Error uploading report to S3: %v\n", err)
+			} else {
+				fmt.Printf("Report uploaded to S3: s3://%s/%s\n", s3BucketName, fileName)
+			}
+		}()
+
+		type reportResp struct {
+			*finance.RangeSummary
+			ReportText string `json:"report_text"`
+			S3File     string `json:"s3_file"`
+		}
+		ok(w, reportResp{
+			RangeSummary: sum,
+			ReportText:   reportText,
+			S3File:       fmt.Sprintf("s3://%s/%s", s3BucketName, fileName),
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postAPITokenReq struct {
+	Name      string          `json:"name"`
+	Scopes    []finance.Scope `json:"scopes"`     // transactions:read | transactions:write | reports:read
+	ExpiresAt string          `json:"expires_at"` // opcional: RFC3339 ou YYYY-MM-DD (padrão 90 dias)
+}
+
+// apiTokenResp inclui o segredo, devolvido apenas na criação
+type apiTokenResp struct {
+	*finance.APIToken
+	Token string `json:"token"`
+}
+
+func postAPIToken(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postAPITokenReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		var exp time.Time
+		if in.ExpiresAt != "" {
+			var err error
+			if exp, err = parseAt(in.ExpiresAt); err != nil {
+				serr(w, errString("expires_at must be RFC3339 or YYYY-MM-DD"), http.StatusBadRequest)
+				return
+			}
+		}
+		t, secret, err := svc.CreateAPIToken(r.Context(), finance.APITokenInput{
+			Name:      in.Name,
+			Scopes:    in.Scopes,
+			ExpiresAt: exp,
+		})
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, apiTokenResp{APIToken: t, Token: secret})
+	}
+}
+
+func listAPITokens(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ts, err := svc.ListAPITokens(r.Context())
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		ok(w, ts)
+	}
+}
+
+func revokeAPIToken(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		if err := svc.RevokeAPIToken(r.Context(), id); err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
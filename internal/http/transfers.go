@@ -0,0 +1,47 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/vinimax001/finance-tracker/internal/finance"
+)
+
+type postTransferReq struct {
+	FromAccountID uuid.UUID `json:"from_account_id"`
+	ToAccountID   uuid.UUID `json:"to_account_id"`
+	AmountCents   int64     `json:"amount_cents"`
+	Description   string    `json:"description"` // opcional
+	OccurredAt    string    `json:"occurred_at"` // opcional: RFC3339 ou YYYY-MM-DD
+}
+
+func postTransfer(svc *finance.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in postTransferReq
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			serr(w, err, http.StatusBadRequest)
+			return
+		}
+		tIn := finance.TransferInput{
+			FromAccountID: in.FromAccountID,
+			ToAccountID:   in.ToAccountID,
+			AmountCents:   in.AmountCents,
+			Description:   in.Description,
+		}
+		if in.OccurredAt != "" {
+			at, err := parseOccurredAt(in.OccurredAt)
+			if err != nil {
+				serr(w, err, http.StatusBadRequest)
+				return
+			}
+			tIn.OccurredAt = at
+		}
+		t, err := svc.CreateTransfer(r.Context(), tIn)
+		if err != nil {
+			serr(w, err, errStatus(err))
+			return
+		}
+		created(w, t)
+	}
+}
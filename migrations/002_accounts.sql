@@ -0,0 +1,16 @@
+-- Contas (corrente, poupança, cartão de crédito, dinheiro)
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('checking','savings','credit_card','cash')),
+    currency CHAR(3) NOT NULL DEFAULT 'BRL',
+    opening_balance_cents BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Transações podem (opcionalmente) pertencer a uma conta
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_occurred_at ON transactions (account_id, occurred_at);
@@ -0,0 +1,18 @@
+-- Transferências entre contas: duas pernas (out/in) ligadas por transfer_id
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_type_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('income','expense','transfer'));
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS transfer_id UUID,
+    ADD COLUMN IF NOT EXISTS direction TEXT CHECK (direction IN ('out','in'));
+
+-- Pernas de transferência sempre têm conta, grupo e direção; as demais nunca têm
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transfer_legs_check;
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_transfer_legs_check CHECK (
+        (type = 'transfer' AND transfer_id IS NOT NULL AND direction IS NOT NULL AND account_id IS NOT NULL)
+        OR (type <> 'transfer' AND transfer_id IS NULL AND direction IS NULL)
+    );
+
+CREATE INDEX IF NOT EXISTS idx_transactions_transfer_id ON transactions (transfer_id) WHERE transfer_id IS NOT NULL;
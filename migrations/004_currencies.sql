@@ -0,0 +1,33 @@
+-- Moeda (ISO 4217) de cada transação; histórico existente é BRL
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+
+-- Cotações diárias: 1 from_currency = rate to_currency a partir de rate_date
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    from_currency CHAR(3) NOT NULL,
+    to_currency CHAR(3) NOT NULL,
+    rate_date DATE NOT NULL,
+    rate NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+    PRIMARY KEY (from_currency, to_currency, rate_date)
+);
+
+-- fx_convert converte centavos para to_cur com a cotação vigente em "at" (direta ou
+-- inversa, preferindo a mais recente). Retorna NULL quando não há cotação.
+CREATE OR REPLACE FUNCTION fx_convert(amount BIGINT, from_cur TEXT, to_cur TEXT, at TIMESTAMPTZ)
+RETURNS BIGINT LANGUAGE sql STABLE AS $$
+    SELECT CASE WHEN from_cur = to_cur THEN amount ELSE (
+        SELECT ROUND(amount * r.rate)::BIGINT
+        FROM (
+            SELECT rate, rate_date, 0 AS pref
+            FROM exchange_rates
+            WHERE from_currency = from_cur AND to_currency = to_cur
+              AND rate_date <= (at AT TIME ZONE 'UTC')::date
+            UNION ALL
+            SELECT 1 / rate, rate_date, 1
+            FROM exchange_rates
+            WHERE from_currency = to_cur AND to_currency = from_cur
+              AND rate_date <= (at AT TIME ZONE 'UTC')::date
+        ) r
+        ORDER BY r.rate_date DESC, r.pref
+        LIMIT 1
+    ) END
+$$;
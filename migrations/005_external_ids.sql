@@ -0,0 +1,7 @@
+-- Identificador do lançamento no banco (FITID do OFX), usado para não duplicar importações
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS external_id TEXT;
+
+-- Único por conta; transações sem conta compartilham o mesmo escopo
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_external_id_uq
+    ON transactions (COALESCE(account_id, '00000000-0000-0000-0000-000000000000'::uuid), external_id)
+    WHERE external_id IS NOT NULL;
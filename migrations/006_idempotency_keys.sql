@@ -0,0 +1,10 @@
+-- Idempotency-Key do POST /transactions: hash da requisição e resposta original
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT PRIMARY KEY,
+    request_hash TEXT NOT NULL,
+    response BYTEA NOT NULL, -- bytes exatos do corpo devolvido
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,31 @@
+-- Usuários da API (senha com bcrypt)
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY,
+    email TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Todo dado passa a pertencer a um dono. Linhas anteriores ficam com o UUID nulo e não
+-- são visíveis a nenhum usuário; para migrá-las:
+--   UPDATE accounts SET owner_id = '<id do usuário>' WHERE owner_id = '00000000-0000-0000-0000-000000000000';
+--   UPDATE transactions SET owner_id = '<id do usuário>' WHERE owner_id = '00000000-0000-0000-0000-000000000000';
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS owner_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS owner_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+
+CREATE INDEX IF NOT EXISTS idx_accounts_owner ON accounts (owner_id, name);
+CREATE INDEX IF NOT EXISTS idx_transactions_owner_occurred_at ON transactions (owner_id, occurred_at);
+
+-- FITID passa a ser único por dono e conta
+DROP INDEX IF EXISTS transactions_external_id_uq;
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_external_id_uq
+    ON transactions (owner_id, COALESCE(account_id, '00000000-0000-0000-0000-000000000000'::uuid), external_id)
+    WHERE external_id IS NOT NULL;
+
+-- Idempotency-Keys também são por dono
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS owner_id UUID NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000';
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (owner_id, key);
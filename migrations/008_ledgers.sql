@@ -0,0 +1,35 @@
+-- Livros-caixa compartilhados (ex: a casa da família) e seus membros
+CREATE TABLE IF NOT EXISTS ledgers (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS ledger_members (
+    ledger_id UUID NOT NULL REFERENCES ledgers (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('owner','editor','viewer')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (ledger_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_members_user ON ledger_members (user_id);
+
+-- Cada usuário existente ganha o livro pessoal com o mesmo id; assim os dados gravados
+-- com owner_id = id do usuário continuam no lugar após a troca de owner_id por ledger_id
+INSERT INTO ledgers (id, name, created_at)
+    SELECT id, 'Pessoal', created_at FROM users
+    ON CONFLICT DO NOTHING;
+INSERT INTO ledger_members (ledger_id, user_id, role, created_at)
+    SELECT id, id, 'owner', created_at FROM users
+    ON CONFLICT DO NOTHING;
+
+DO $$
+DECLARE t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['accounts', 'transactions', 'idempotency_keys'] LOOP
+        IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = t AND column_name = 'owner_id') THEN
+            EXECUTE format('ALTER TABLE %I RENAME COLUMN owner_id TO ledger_id', t);
+        END IF;
+    END LOOP;
+END $$;
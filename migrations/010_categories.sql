@@ -0,0 +1,33 @@
+-- Categorias gerenciadas por livro, com hierarquia (ex: Moradia > Aluguel) e apelidos.
+-- As transações continuam guardando o nome canônico da categoria.
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers (id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES categories (id) ON DELETE RESTRICT,
+    name TEXT NOT NULL,
+    aliases JSONB NOT NULL DEFAULT '[]', -- array de nomes alternativos
+    color TEXT NOT NULL DEFAULT '',
+    icon TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_categories_ledger_name ON categories (ledger_id, lower(name));
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories (parent_id);
+
+-- Backfill: uma categoria raiz por valor distinto já usado, agrupando variações de
+-- maiúsculas e espaços ("Food" e "food "); as transações passam a usar o nome escolhido
+INSERT INTO categories (id, ledger_id, name)
+    SELECT gen_random_uuid(), ledger_id, MIN(regexp_replace(btrim(category), '\s+', ' ', 'g'))
+    FROM transactions
+    WHERE type <> 'transfer' AND btrim(category) <> ''
+    GROUP BY ledger_id, lower(regexp_replace(btrim(category), '\s+', ' ', 'g'))
+    ON CONFLICT DO NOTHING;
+
+UPDATE transactions t
+SET category = c.name
+FROM categories c
+WHERE c.ledger_id = t.ledger_id
+  AND t.type <> 'transfer'
+  AND lower(regexp_replace(btrim(t.category), '\s+', ' ', 'g')) = lower(c.name)
+  AND t.category <> c.name;
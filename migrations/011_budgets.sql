@@ -0,0 +1,13 @@
+-- Limites mensais de gasto por categoria; month = '' vale para todos os meses e o
+-- orçamento de um mês específico ('YYYY-MM') prevalece sobre o recorrente
+CREATE TABLE IF NOT EXISTS budgets (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers (id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    month TEXT NOT NULL DEFAULT '' CHECK (month = '' OR month ~ '^[0-9]{4}-[0-9]{2}$'),
+    limit_cents BIGINT NOT NULL CHECK (limit_cents > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_budgets_category_month ON budgets (ledger_id, category_id, month);
@@ -0,0 +1,26 @@
+-- Regras de transações recorrentes (aluguel, salário, assinaturas). O agendador lança as
+-- ocorrências vencidas (next_at <= agora) e avança done/next_at na mesma transação.
+CREATE TABLE IF NOT EXISTS recurring_rules (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers (id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('income','expense')),
+    category TEXT NOT NULL,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    currency CHAR(3) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    account_id UUID REFERENCES accounts (id),
+    frequency TEXT NOT NULL CHECK (frequency IN ('weekly','monthly','yearly')),
+    interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    day_of_month INT NOT NULL DEFAULT 0 CHECK (day_of_month BETWEEN 0 AND 31),
+    start_at TIMESTAMPTZ NOT NULL,
+    until TIMESTAMPTZ,
+    occurrence_limit INT NOT NULL DEFAULT 0, -- 0 = sem limite
+    skipped JSONB NOT NULL DEFAULT '[]',     -- datas YYYY-MM-DD puladas
+    done INT NOT NULL DEFAULT 0,             -- ocorrências já processadas
+    next_at TIMESTAMPTZ,                     -- NULL quando a regra terminou
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_ledger ON recurring_rules (ledger_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_rules_due ON recurring_rules (next_at) WHERE next_at IS NOT NULL;
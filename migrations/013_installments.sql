@@ -0,0 +1,23 @@
+-- Compras parceladas: cada parcela é uma despesa ligada à compra por installment_id
+CREATE TABLE IF NOT EXISTS installments (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers (id) ON DELETE CASCADE,
+    description TEXT NOT NULL DEFAULT '',
+    total_cents BIGINT NOT NULL CHECK (total_cents > 0),
+    currency CHAR(3) NOT NULL,
+    installments INT NOT NULL CHECK (installments BETWEEN 2 AND 120),
+    remainder TEXT NOT NULL DEFAULT 'first' CHECK (remainder IN ('first','last')), -- parcela que recebe o resto
+    first_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active','cancelled','prepaid')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_installments_ledger ON installments (ledger_id, first_at DESC);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS installment_id UUID REFERENCES installments (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS installment_number INT CHECK (installment_number > 0);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_installment ON transactions (installment_id, installment_number)
+    WHERE installment_id IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Ciclo de fatura dos cartões de crédito e vínculo dos pagamentos com a fatura paga
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS closing_day INT CHECK (closing_day BETWEEN 1 AND 31),
+    ADD COLUMN IF NOT EXISTS due_day INT CHECK (due_day BETWEEN 1 AND 31);
+
+-- Período (YYYY-MM do fechamento) da fatura paga pela transferência
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS statement TEXT CHECK (statement ~ '^[0-9]{4}-[0-9]{2}$');
+
+CREATE INDEX IF NOT EXISTS idx_transactions_statement ON transactions (account_id, statement)
+    WHERE statement IS NOT NULL;
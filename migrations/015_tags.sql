@@ -0,0 +1,20 @@
+-- Tags: rótulos livres por livro (ex: "viagem-lisboa", "reembolsavel"), N:N com transações
+CREATE TABLE IF NOT EXISTS tags (
+    ledger_id UUID NOT NULL REFERENCES ledgers (id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (name = lower(btrim(name)) AND length(name) BETWEEN 1 AND 50),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (ledger_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_tags (
+    transaction_id UUID NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    ledger_id UUID NOT NULL,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (transaction_id, tag),
+    FOREIGN KEY (ledger_id, tag) REFERENCES tags (ledger_id, name) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_tags_tag ON transaction_tags (ledger_id, tag);
+
+-- Regras recorrentes repassam as tags às transações geradas
+ALTER TABLE recurring_rules ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
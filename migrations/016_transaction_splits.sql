@@ -0,0 +1,12 @@
+-- Rateio de uma transação entre categorias; a soma das linhas é igual a amount_cents
+-- (validado pela aplicação). transactions.category guarda a categoria principal.
+CREATE TABLE IF NOT EXISTS transaction_splits (
+    transaction_id UUID NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    line INT NOT NULL,
+    category TEXT NOT NULL,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    note TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (transaction_id, line)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_splits_category ON transaction_splits (category);
@@ -0,0 +1,18 @@
+-- Metas de economia: juntar target_cents até target_date, acompanhando o saldo de uma conta
+-- ou o das transações com uma tag (exatamente um dos dois). A FK sem cascata impede remover
+-- a conta enquanto houver meta vinculada.
+CREATE TABLE IF NOT EXISTS goals (
+    id UUID PRIMARY KEY,
+    ledger_id UUID NOT NULL REFERENCES ledgers (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    target_cents BIGINT NOT NULL CHECK (target_cents > 0),
+    currency CHAR(3) NOT NULL,
+    target_date DATE NOT NULL,
+    account_id UUID REFERENCES accounts (id),
+    tag TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((account_id IS NULL) <> (tag = ''))
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_ledger ON goals (ledger_id, target_date);
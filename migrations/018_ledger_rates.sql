@@ -0,0 +1,40 @@
+-- Cotações por livro: antes eram globais e qualquer usuário podia alterar as conversões
+-- dos demais. As existentes são copiadas para todos os livros.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'exchange_rates' AND column_name = 'ledger_id') THEN
+        ALTER TABLE exchange_rates ADD COLUMN ledger_id UUID REFERENCES ledgers (id) ON DELETE CASCADE;
+        INSERT INTO exchange_rates (ledger_id, from_currency, to_currency, rate_date, rate)
+            SELECT l.id, r.from_currency, r.to_currency, r.rate_date, r.rate
+            FROM ledgers l CROSS JOIN exchange_rates r
+            WHERE r.ledger_id IS NULL;
+        DELETE FROM exchange_rates WHERE ledger_id IS NULL;
+        ALTER TABLE exchange_rates ALTER COLUMN ledger_id SET NOT NULL;
+        ALTER TABLE exchange_rates DROP CONSTRAINT exchange_rates_pkey;
+        ALTER TABLE exchange_rates ADD PRIMARY KEY (ledger_id, from_currency, to_currency, rate_date);
+    END IF;
+END $$;
+
+DROP FUNCTION IF EXISTS fx_convert(BIGINT, TEXT, TEXT, TIMESTAMPTZ);
+
+-- fx_convert converte centavos para to_cur com a cotação do livro vigente em "at" (direta
+-- ou inversa, preferindo a mais recente). Retorna NULL quando não há cotação.
+CREATE OR REPLACE FUNCTION fx_convert(ledger UUID, amount BIGINT, from_cur TEXT, to_cur TEXT, at TIMESTAMPTZ)
+RETURNS BIGINT LANGUAGE sql STABLE AS $$
+    SELECT CASE WHEN from_cur = to_cur THEN amount ELSE (
+        SELECT ROUND(amount * r.rate)::BIGINT
+        FROM (
+            SELECT rate, rate_date, 0 AS pref
+            FROM exchange_rates
+            WHERE ledger_id = ledger AND from_currency = from_cur AND to_currency = to_cur
+              AND rate_date <= (at AT TIME ZONE 'UTC')::date
+            UNION ALL
+            SELECT 1 / rate, rate_date, 1
+            FROM exchange_rates
+            WHERE ledger_id = ledger AND from_currency = to_cur AND to_currency = from_cur
+              AND rate_date <= (at AT TIME ZONE 'UTC')::date
+        ) r
+        ORDER BY r.rate_date DESC, r.pref
+        LIMIT 1
+    ) END
+$$;